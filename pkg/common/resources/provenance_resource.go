@@ -0,0 +1,91 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+)
+
+// ProvenanceResource is a utility struct to accumulate resources while tracking the contribution of each source.
+// The contributions are tracked per resource type and source label, which allows finding the source responsible for
+// a total without instrumenting every caller.
+type ProvenanceResource struct {
+	total   *Resource
+	sources map[string]map[string]Quantity // resource type -> source label -> contribution
+
+	locking.RWMutex
+}
+
+// NewProvenanceResource creates a new instance of ProvenanceResource without any contributions.
+func NewProvenanceResource() *ProvenanceResource {
+	return &ProvenanceResource{
+		total:   NewResource(),
+		sources: make(map[string]map[string]Quantity),
+	}
+}
+
+func (pr *ProvenanceResource) String() string {
+	if pr == nil {
+		return "ProvenanceResource{}"
+	}
+	pr.RLock()
+	defer pr.RUnlock()
+	return fmt.Sprintf("ProvenanceResource{total=%s,sources=%v}", pr.total, pr.sources)
+}
+
+// AddFrom adds the resource to the total and records the contribution for the source label.
+// Multiple additions from the same label are accumulated. A nil resource does not change anything.
+// The additions are protected from overflow (positive and negative).
+func (pr *ProvenanceResource) AddFrom(label string, res *Resource) {
+	if res == nil {
+		return
+	}
+	pr.Lock()
+	defer pr.Unlock()
+	pr.total.AddTo(res)
+	for k, v := range res.Resources {
+		contributions, ok := pr.sources[k]
+		if !ok {
+			contributions = make(map[string]Quantity)
+			pr.sources[k] = contributions
+		}
+		contributions[label] = addVal(contributions[label], v)
+	}
+}
+
+// Total returns a clone of the accumulated resources from all sources.
+func (pr *ProvenanceResource) Total() *Resource {
+	pr.RLock()
+	defer pr.RUnlock()
+	return pr.total.Clone()
+}
+
+// Breakdown returns a copy of the contributions for the resource type keyed by the source label.
+// A resource type without contributions returns an empty map.
+func (pr *ProvenanceResource) Breakdown(resourceType string) map[string]Quantity {
+	pr.RLock()
+	defer pr.RUnlock()
+	breakdown := make(map[string]Quantity)
+	for label, v := range pr.sources[resourceType] {
+		breakdown[label] = v
+	}
+	return breakdown
+}
@@ -0,0 +1,60 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNewProvenanceResource(t *testing.T) {
+	pr := NewProvenanceResource()
+	assert.Assert(t, IsZero(pr.Total()), "new instance should have an empty total")
+	assert.Equal(t, len(pr.Breakdown("first")), 0, "new instance should have no contributions")
+	assert.Equal(t, pr.String(), "ProvenanceResource{total=map[],sources=map[]}")
+
+	var empty *ProvenanceResource
+	assert.Equal(t, empty.String(), "ProvenanceResource{}")
+}
+
+func TestProvenanceResourceAddFrom(t *testing.T) {
+	pr := NewProvenanceResource()
+	pr.AddFrom("app-1", NewResourceFromMap(map[string]Quantity{"first": 5, "second": 1}))
+	pr.AddFrom("app-2", NewResourceFromMap(map[string]Quantity{"first": 3}))
+	pr.AddFrom("app-1", NewResourceFromMap(map[string]Quantity{"first": 2, "second": -1}))
+	pr.AddFrom("app-3", nil)
+	assert.Assert(t, DeepEquals(pr.Total(), NewResourceFromMap(map[string]Quantity{"first": 10, "second": 0})), "unexpected total: %v", pr.Total())
+	assert.DeepEqual(t, pr.Breakdown("first"), map[string]Quantity{"app-1": 7, "app-2": 3})
+	assert.DeepEqual(t, pr.Breakdown("second"), map[string]Quantity{"app-1": 0})
+	assert.Equal(t, len(pr.Breakdown("third")), 0, "undefined type should have no contributions")
+
+	// returned values must not change the internal state
+	total := pr.Total()
+	total.Resources["first"] = 100
+	breakdown := pr.Breakdown("first")
+	breakdown["app-1"] = 100
+	assert.Equal(t, pr.Total().Resources["first"], Quantity(10), "total changed via returned resource")
+	assert.Equal(t, pr.Breakdown("first")["app-1"], Quantity(7), "breakdown changed via returned map")
+
+	// contributions are protected from overflow
+	pr.AddFrom("app-2", NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}))
+	assert.Equal(t, pr.Breakdown("first")["app-2"], Quantity(math.MaxInt64), "contribution should not wrap")
+}
@@ -33,9 +33,10 @@ import (
 // <suffix>       ::= <binarySI> | <decimalSI>
 // <binarySI>     ::= Ki | Mi | Gi | Ti | Pi | Ei
 // <decimalSI>    ::= "" | k | M | G | T | P | E
-// Additionally, ParseVCore supports decimalSI of 'm' to indicate millicores.
+// Additionally, ParseVCore supports decimalSI of 'm' to indicate millicores, and a decimal number
+// (<digits>.<digits>) to indicate fractional cores as long as the 'm' suffix is not used.
 
-var legal = regexp.MustCompile(`^(?P<Number>[0-9]+)\s*(?P<Suffix>([mkKMGTPE]i?)?)$`)
+var legal = regexp.MustCompile(`^(?P<Number>[0-9]+)(?P<Fraction>\.[0-9]+)?\s*(?P<Suffix>([mkKMGTPE]i?)?)$`)
 
 var multipliers = map[string]int64{
 	"":   1,
@@ -54,6 +55,34 @@ var multipliers = map[string]int64{
 	"Ei": 1 << 60,
 }
 
+// Cores converts a vcore quantity, in millicores as returned by ParseVCore, into a number of cores.
+func (q Quantity) Cores() float64 {
+	return q.Milli()
+}
+
+// Milli converts a quantity stored in thousandths of a unit, as returned by ParseMilliQuantity, into a number of units.
+func (q Quantity) Milli() float64 {
+	return float64(q) / 1000
+}
+
+// CoresToQuantity converts a number of cores into a vcore quantity in millicores, the unit used by ParseVCore.
+// The result is rounded to the nearest millicore and protected from overflow. Unlike ParseVCore a value with more
+// precision than a millicore is not rejected.
+func CoresToQuantity(c float64) Quantity {
+	return mulValRatioRound(1000, c)
+}
+
+// Gibibytes converts a quantity in bytes, as returned by ParseQuantity, into a number of gibibytes (1Gi).
+func (q Quantity) Gibibytes() float64 {
+	return float64(q) / float64(multipliers["Gi"])
+}
+
+// GibibytesToQuantity converts a number of gibibytes (1Gi) into a quantity in bytes, the unit used by ParseQuantity.
+// The result is rounded to the nearest byte and protected from overflow.
+func GibibytesToQuantity(g float64) Quantity {
+	return mulValRatioRound(Quantity(multipliers["Gi"]), g)
+}
+
 // ParseQuantity is used to parse user-provided values into int64 quantities.
 func ParseQuantity(value string) (Quantity, error) {
 	return parse(value, false)
@@ -62,10 +91,33 @@ func ParseQuantity(value string) (Quantity, error) {
 // ParseVCore is similar to ParseQuantity but allows the 'm' suffix. Additionally, the base unit returned is a
 // millicore, so values without units will be converted to milliCPUs (i.e. '10' will result in 10000, and '500m' will
 // result in 500).
+// Fractional cores are supported using a decimal number, '0.5' will result in 500. The fractional value must resolve
+// to a whole number of millicores and cannot be combined with the 'm' suffix (i.e. '500.5m' is rejected).
 func ParseVCore(value string) (Quantity, error) {
 	return parse(value, true)
 }
 
+// ParseMilliQuantity parses a value for a resource type that needs sub-integer precision, like fractional GPUs.
+// The quantity is stored in thousandths of a unit: '0.5' will result in 500 and '250m' will result in 250.
+// The same rules as for ParseVCore apply.
+func ParseMilliQuantity(value string) (Quantity, error) {
+	return parse(value, true)
+}
+
+// ParseCount is similar to ParseQuantity but rejects fractional values with a specific error. Count types are whole
+// numbers: binary and decimal SI suffixes are still allowed, '1k' will result in 1000, as the value remains a whole
+// number.
+func ParseCount(value string) (Quantity, error) {
+	parts := legal.FindStringSubmatch(strings.TrimSpace(value))
+	if len(parts) == 0 {
+		return 0, errors.New("invalid quantity")
+	}
+	if parts[2] != "" {
+		return 0, errors.New("invalid quantity: fractional value not allowed")
+	}
+	return parse(value, false)
+}
+
 func parse(value string, milli bool) (Quantity, error) {
 	value = strings.TrimSpace(value)
 
@@ -74,7 +126,12 @@ func parse(value string, milli bool) (Quantity, error) {
 		return 0, errors.New("invalid quantity")
 	}
 	number := parts[1]
-	suffix := parts[2]
+	fraction := parts[2]
+	suffix := parts[3]
+
+	if fraction != "" {
+		return parseFraction(number+fraction, suffix, milli)
+	}
 
 	result, err := strconv.ParseInt(number, 10, 64)
 	if err != nil {
@@ -99,3 +156,31 @@ func parse(value string, milli bool) (Quantity, error) {
 
 	return Quantity(result), nil
 }
+
+// parseFraction handles a decimal number, only allowed when parsing vcores without the 'm' suffix.
+// The scaled result must be a whole number of millicores.
+func parseFraction(number string, suffix string, milli bool) (Quantity, error) {
+	if !milli {
+		return 0, errors.New("invalid quantity: fractional value not allowed")
+	}
+	if suffix == "m" {
+		return 0, errors.New("invalid quantity: fractional value not allowed with milli suffix")
+	}
+	scale, ok := multipliers[suffix]
+	if !ok {
+		return 0, errors.New("invalid suffix")
+	}
+	bigResult, ok := new(big.Rat).SetString(number)
+	if !ok {
+		return 0, errors.New("invalid quantity")
+	}
+	bigResult.Mul(bigResult, new(big.Rat).SetInt64(scale))
+	bigResult.Mul(bigResult, new(big.Rat).SetInt64(1000))
+	if !bigResult.IsInt() {
+		return 0, errors.New("invalid quantity: fractional value smaller than a millicore")
+	}
+	if !bigResult.Num().IsInt64() {
+		return 0, errors.New("invalid quantity: overflow")
+	}
+	return Quantity(bigResult.Num().Int64()), nil
+}
@@ -19,6 +19,8 @@
 package resources
 
 import (
+	"math"
+	"strconv"
 	"testing"
 
 	"gotest.tools/v3/assert"
@@ -40,6 +42,8 @@ func TestParseQuantity(t *testing.T) {
 		"wrong unit": {input: "5X", qty: 0, err: "invalid"},
 		"milli":      {input: "500m", qty: 0, err: "invalid"},
 		"negative":   {input: "-1", qty: 0, err: "invalid"},
+		"fraction":   {input: "1.5", qty: 0, err: "fractional value not allowed"},
+		"fraction2":  {input: "1.5Gi", qty: 0, err: "fractional value not allowed"},
 		"2k":         {input: "2k", qty: 2 * 1000},
 		"3M":         {input: "3M", qty: 3 * 1000 * 1000},
 		"4G":         {input: "4G", qty: 4 * 1000 * 1000 * 1000},
@@ -106,3 +110,151 @@ func TestParseVCore(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMilliQuantity(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		qty   Quantity
+		err   string
+	}{
+		"0":           {input: "0", qty: 0},
+		"1":           {input: "1", qty: 1000},
+		"0.5":         {input: "0.5", qty: 500},
+		"250m":        {input: "250m", qty: 250},
+		"2k":          {input: "2k", qty: 2 * 1000 * 1000},
+		"too precise": {input: "0.0005", qty: 0, err: "smaller than a millicore"},
+		"overflow":    {input: "9223372036854776", qty: 0, err: "overflow"},
+		"negative":    {input: "-0.5", qty: 0, err: "invalid"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseMilliQuantity(test.input)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err, "error expected")
+			} else {
+				assert.NilError(t, err, "no error expected")
+				assert.Equal(t, result, test.qty, "wrong result")
+				assert.Equal(t, result.Milli(), float64(test.qty)/1000, "wrong units")
+			}
+		})
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		qty   Quantity
+		err   string
+	}{
+		"0":          {input: "0", qty: 0},
+		"1":          {input: "1", qty: 1},
+		"spaces":     {input: " 110 ", qty: 110},
+		"max":        {input: "9223372036854775807", qty: 9223372036854775807},
+		"overflow":   {input: "9223372036854775808", qty: 0, err: "overflow"},
+		"suffix":     {input: "1k", qty: 1000},
+		"binary":     {input: "1Ki", qty: 1024},
+		"milli":      {input: "500m", qty: 0, err: "invalid suffix"},
+		"wrong unit": {input: "5X", qty: 0, err: "invalid"},
+		"negative":   {input: "-1", qty: 0, err: "invalid"},
+		"fraction":   {input: "1.5", qty: 0, err: "fractional value not allowed"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseCount(test.input)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err, "error expected")
+			} else {
+				assert.NilError(t, err, "no error expected")
+				assert.Equal(t, result, test.qty, "wrong result")
+			}
+		})
+	}
+}
+
+func TestParseVCoreFraction(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		qty   Quantity
+		err   string
+	}{
+		"1":             {input: "1", qty: 1000},
+		"1000m":         {input: "1000m", qty: 1000},
+		"1.0":           {input: "1.0", qty: 1000},
+		"0.5":           {input: "0.5", qty: 500},
+		"0.001":         {input: "0.001", qty: 1},
+		"1m":            {input: "1m", qty: 1},
+		"1.5k":          {input: "1.5k", qty: 1500 * 1000},
+		"0.5Ki":         {input: "0.5Ki", qty: 512 * 1000},
+		"spaces":        {input: " 2.25  ", qty: 2250},
+		"trailing zero": {input: "0.1000", qty: 100},
+		"milli frac":    {input: "500.5m", qty: 0, err: "not allowed with milli suffix"},
+		"too precise":   {input: "0.0005", qty: 0, err: "smaller than a millicore"},
+		"overflow":      {input: "9223372036854775.808", qty: 0, err: "overflow"},
+		"no integer":    {input: ".5", qty: 0, err: "invalid"},
+		"no fraction":   {input: "1.", qty: 0, err: "invalid"},
+		"two dots":      {input: "1.2.3", qty: 0, err: "invalid"},
+		"comma":         {input: "0,5", qty: 0, err: "invalid"},
+		"negative":      {input: "-0.5", qty: 0, err: "invalid"},
+		"wrong unit":    {input: "0.5X", qty: 0, err: "invalid"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := ParseVCore(test.input)
+			if test.err != "" {
+				assert.ErrorContains(t, err, test.err, "error expected")
+			} else {
+				assert.NilError(t, err, "no error expected")
+				assert.Equal(t, result, test.qty, "wrong result")
+			}
+		})
+	}
+}
+
+func TestQuantityConversion(t *testing.T) {
+	assert.Equal(t, Quantity(1500).Cores(), 1.5, "unexpected cores")
+	assert.Equal(t, Quantity(0).Cores(), 0.0, "unexpected cores")
+	assert.Equal(t, Quantity(-250).Cores(), -0.25, "unexpected cores")
+	assert.Equal(t, Quantity(500).Milli(), 0.5, "unexpected units")
+	assert.Equal(t, Quantity(2000).Milli(), 2.0, "unexpected units")
+	assert.Equal(t, CoresToQuantity(1.5), Quantity(1500), "unexpected quantity")
+	assert.Equal(t, CoresToQuantity(0.0014), Quantity(1), "quantity should be rounded to nearest")
+	assert.Equal(t, CoresToQuantity(0.0016), Quantity(2), "quantity should be rounded to nearest")
+	assert.Equal(t, CoresToQuantity(-0.0016), Quantity(-2), "quantity should be rounded to nearest")
+	assert.Equal(t, CoresToQuantity(math.MaxInt64), Quantity(math.MaxInt64), "quantity should not overflow")
+
+	assert.Equal(t, Quantity(3*1024*1024*1024).Gibibytes(), 3.0, "unexpected gibibytes")
+	assert.Equal(t, Quantity(512*1024*1024).Gibibytes(), 0.5, "unexpected gibibytes")
+	assert.Equal(t, GibibytesToQuantity(1.5), Quantity(1536*1024*1024), "unexpected quantity")
+	assert.Equal(t, GibibytesToQuantity(math.MaxInt64), Quantity(math.MaxInt64), "quantity should not overflow")
+	assert.Equal(t, GibibytesToQuantity(-math.MaxInt64), Quantity(math.MinInt64), "quantity should not overflow")
+
+	// must match the parsing of the config units
+	for _, value := range []string{"1", "0.5", "250m", "2k", "1.001", "0.57", "99.999"} {
+		vcore, err := ParseVCore(value)
+		assert.NilError(t, err, "unexpected parse error")
+		assert.Equal(t, CoresToQuantity(vcore.Cores()), vcore, "round trip failed for %s", value)
+		cores, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			assert.Equal(t, CoresToQuantity(cores), vcore, "conversion differs from parsing for %s", value)
+		}
+	}
+	for _, value := range []string{"1Gi", "1536Mi", "2Ki", "3G"} {
+		memory, err := ParseQuantity(value)
+		assert.NilError(t, err, "unexpected parse error")
+		assert.Equal(t, GibibytesToQuantity(memory.Gibibytes()), memory, "round trip failed for %s", value)
+	}
+
+	// every quantity must survive the float conversion unchanged
+	for q := Quantity(-100000); q < 100000; q++ {
+		if got := CoresToQuantity(q.Cores()); got != q {
+			t.Fatalf("cores round trip failed for %d: got %d", q, got)
+		}
+		if got := GibibytesToQuantity(q.Gibibytes()); got != q {
+			t.Fatalf("gibibytes round trip failed for %d: got %d", q, got)
+		}
+	}
+	for _, q := range []Quantity{1 << 40, 1<<40 + 1, 1<<50 - 1, 123456789012345} {
+		assert.Equal(t, CoresToQuantity(q.Cores()), q, "cores round trip failed for %d", q)
+		assert.Equal(t, GibibytesToQuantity(q.Gibibytes()), q, "gibibytes round trip failed for %d", q)
+	}
+}
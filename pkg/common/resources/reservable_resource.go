@@ -0,0 +1,114 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+)
+
+// ReservableResource is a utility struct to track committed and tentatively reserved resources against a capacity.
+// Resources are first reserved, if they fit in the capacity, and later committed or released.
+type ReservableResource struct {
+	capacity  *Resource
+	committed *Resource
+	reserved  *Resource
+
+	locking.RWMutex
+}
+
+// NewReservableResource creates a new instance of ReservableResource with nothing committed or reserved.
+// A nil capacity is treated as an empty resource: nothing can be reserved.
+func NewReservableResource(capacity *Resource) *ReservableResource {
+	if capacity == nil {
+		capacity = NewResource()
+	}
+	return &ReservableResource{
+		capacity:  capacity.Clone(),
+		committed: NewResource(),
+		reserved:  NewResource(),
+	}
+}
+
+func (rr *ReservableResource) String() string {
+	if rr == nil {
+		return "ReservableResource{}"
+	}
+	rr.RLock()
+	defer rr.RUnlock()
+	return fmt.Sprintf("ReservableResource{capacity=%s,committed=%s,reserved=%s}", rr.capacity, rr.committed, rr.reserved)
+}
+
+// Reserve adds the resource to the reserved resources if the committed, reserved and requested resources together
+// fit in the capacity. Returns true if the reservation was made, false otherwise.
+// A nil resource always fits and does not change the reservation.
+func (rr *ReservableResource) Reserve(res *Resource) bool {
+	// checked before the fit: an over committed capacity would reject it
+	if res == nil {
+		return true
+	}
+	rr.Lock()
+	defer rr.Unlock()
+	if !rr.capacity.FitIn(AddMany(rr.committed, rr.reserved, res)) {
+		return false
+	}
+	rr.reserved.AddTo(res)
+	return true
+}
+
+// Commit moves the resource from the reserved resources to the committed resources.
+// The reserved resources never drop below zero, committing more than was reserved is allowed and is not checked
+// against the capacity.
+func (rr *ReservableResource) Commit(res *Resource) {
+	rr.Lock()
+	defer rr.Unlock()
+	rr.reserved = SubEliminateNegative(rr.reserved, res)
+	rr.committed.AddTo(res)
+}
+
+// ReleaseReservation removes the resource from the reserved resources.
+// The reserved resources never drop below zero.
+func (rr *ReservableResource) ReleaseReservation(res *Resource) {
+	rr.Lock()
+	defer rr.Unlock()
+	rr.reserved = SubEliminateNegative(rr.reserved, res)
+}
+
+// Available returns the capacity minus the committed and reserved resources.
+// This might return negative values for specific quantities.
+func (rr *ReservableResource) Available() *Resource {
+	rr.RLock()
+	defer rr.RUnlock()
+	return SubMany(rr.capacity, rr.committed, rr.reserved)
+}
+
+// GetCommitted returns a clone of the committed resources.
+func (rr *ReservableResource) GetCommitted() *Resource {
+	rr.RLock()
+	defer rr.RUnlock()
+	return rr.committed.Clone()
+}
+
+// GetReserved returns a clone of the reserved resources.
+func (rr *ReservableResource) GetReserved() *Resource {
+	rr.RLock()
+	defer rr.RUnlock()
+	return rr.reserved.Clone()
+}
@@ -0,0 +1,95 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNewReservableResource(t *testing.T) {
+	rr := NewReservableResource(nil)
+	assert.Assert(t, IsZero(rr.Available()), "nil capacity should have nothing available")
+	assert.Assert(t, !rr.Reserve(NewResourceFromMap(map[string]Quantity{"first": 1})), "reserve on nil capacity should fail")
+
+	capacity := NewResourceFromMap(map[string]Quantity{"first": 10})
+	rr = NewReservableResource(capacity)
+	capacity.Resources["first"] = 1
+	assert.Assert(t, Equals(rr.Available(), NewResourceFromMap(map[string]Quantity{"first": 10})), "capacity should be cloned")
+	assert.Assert(t, IsZero(rr.GetCommitted()), "new instance should have nothing committed")
+	assert.Assert(t, IsZero(rr.GetReserved()), "new instance should have nothing reserved")
+	assert.Equal(t, rr.String(), "ReservableResource{capacity=map[first:10],committed=map[],reserved=map[]}")
+
+	var empty *ReservableResource
+	assert.Equal(t, empty.String(), "ReservableResource{}")
+}
+
+func TestReservableResourceReserve(t *testing.T) {
+	rr := NewReservableResource(NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}))
+	assert.Assert(t, rr.Reserve(nil), "nil reservation should always fit")
+	assert.Assert(t, rr.Reserve(NewResourceFromMap(map[string]Quantity{"first": 5})), "reservation should fit")
+	assert.Assert(t, rr.Reserve(NewResourceFromMap(map[string]Quantity{"first": 5, "second": 5})), "reservation up to capacity should fit")
+	assert.Assert(t, !rr.Reserve(NewResourceFromMap(map[string]Quantity{"first": 1})), "reservation over capacity should fail")
+	assert.Assert(t, !rr.Reserve(NewResourceFromMap(map[string]Quantity{"third": 1})), "reservation of undefined type should fail")
+	assert.Assert(t, Equals(rr.GetReserved(), NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5})), "unexpected reserved resource")
+	assert.Assert(t, Equals(rr.Available(), NewResourceFromMap(map[string]Quantity{"first": 0, "second": 5})), "unexpected available resource")
+
+	// committed resources count towards the capacity
+	rr.Commit(NewResourceFromMap(map[string]Quantity{"first": 10}))
+	assert.Assert(t, !rr.Reserve(NewResourceFromMap(map[string]Quantity{"second": 6})), "reservation over capacity should fail")
+	assert.Assert(t, rr.Reserve(NewResourceFromMap(map[string]Quantity{"second": 5})), "reservation should fit")
+	assert.Assert(t, IsZero(rr.Available()), "everything should be used")
+}
+
+func TestReservableResourceCommit(t *testing.T) {
+	rr := NewReservableResource(NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}))
+	assert.Assert(t, rr.Reserve(NewResourceFromMap(map[string]Quantity{"first": 5, "second": 5})), "reservation should fit")
+	rr.Commit(NewResourceFromMap(map[string]Quantity{"first": 5}))
+	assert.Assert(t, Equals(rr.GetCommitted(), NewResourceFromMap(map[string]Quantity{"first": 5})), "unexpected committed resource")
+	assert.Assert(t, Equals(rr.GetReserved(), NewResourceFromMap(map[string]Quantity{"second": 5})), "unexpected reserved resource")
+	assert.Assert(t, Equals(rr.Available(), NewResourceFromMap(map[string]Quantity{"first": 5, "second": 5})), "unexpected available resource")
+
+	// commit more than reserved: reservation does not go negative
+	rr.Commit(NewResourceFromMap(map[string]Quantity{"second": 8}))
+	assert.Assert(t, Equals(rr.GetCommitted(), NewResourceFromMap(map[string]Quantity{"first": 5, "second": 8})), "unexpected committed resource")
+	assert.Assert(t, IsZero(rr.GetReserved()), "reservation should be zero")
+	rr.Commit(nil)
+	assert.Assert(t, Equals(rr.GetCommitted(), NewResourceFromMap(map[string]Quantity{"first": 5, "second": 8})), "nil commit changed committed resource")
+
+	// commit past the capacity: nothing fits except a nil resource
+	rr.Commit(NewResourceFromMap(map[string]Quantity{"first": 10}))
+	assert.Assert(t, Equals(rr.GetCommitted(), NewResourceFromMap(map[string]Quantity{"first": 15, "second": 8})), "unexpected committed resource")
+	assert.Assert(t, !rr.Reserve(NewResourceFromMap(map[string]Quantity{"second": 1})), "reservation should not fit in over committed capacity")
+	assert.Assert(t, rr.Reserve(nil), "nil reservation should always fit")
+	assert.Assert(t, IsZero(rr.GetReserved()), "nil reservation changed reserved resource")
+}
+
+func TestReservableResourceRelease(t *testing.T) {
+	rr := NewReservableResource(NewResourceFromMap(map[string]Quantity{"first": 10}))
+	assert.Assert(t, rr.Reserve(NewResourceFromMap(map[string]Quantity{"first": 8})), "reservation should fit")
+	rr.ReleaseReservation(NewResourceFromMap(map[string]Quantity{"first": 3}))
+	assert.Assert(t, Equals(rr.GetReserved(), NewResourceFromMap(map[string]Quantity{"first": 5})), "unexpected reserved resource")
+	assert.Assert(t, Equals(rr.Available(), NewResourceFromMap(map[string]Quantity{"first": 5})), "unexpected available resource")
+	rr.ReleaseReservation(NewResourceFromMap(map[string]Quantity{"first": 10}))
+	assert.Assert(t, IsZero(rr.GetReserved()), "reservation should not go negative")
+	rr.ReleaseReservation(nil)
+	assert.Assert(t, IsZero(rr.GetReserved()), "nil release changed reservation")
+	assert.Assert(t, IsZero(rr.GetCommitted()), "release changed committed resource")
+}
@@ -0,0 +1,63 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+)
+
+// ResourceBuilder constructs a Resource using chained calls:
+//
+//	res := NewResourceBuilder().WithCPU(1.5).WithMemory(1024).With("pods", 10).Build()
+//
+// The quantities are stored in the same units as NewResourceFromConf uses: CPU in millicores, memory in bytes.
+// Setting the same type more than once keeps the last value.
+type ResourceBuilder struct {
+	resources map[string]Quantity
+}
+
+// NewResourceBuilder creates a new builder without any resource types set.
+func NewResourceBuilder() *ResourceBuilder {
+	return &ResourceBuilder{
+		resources: make(map[string]Quantity),
+	}
+}
+
+// With sets the quantity for the resource type, the value is used as is.
+func (rb *ResourceBuilder) With(key string, value Quantity) *ResourceBuilder {
+	rb.resources[key] = value
+	return rb
+}
+
+// WithCPU sets the CPU quantity from a number of cores: 1.5 cores results in 1500 millicores.
+// The value is rounded to the nearest millicore and protected from overflow.
+func (rb *ResourceBuilder) WithCPU(cores float64) *ResourceBuilder {
+	return rb.With(common.CPU, CoresToQuantity(cores))
+}
+
+// WithMemory sets the memory quantity in bytes.
+func (rb *ResourceBuilder) WithMemory(bytes int64) *ResourceBuilder {
+	return rb.With(common.Memory, Quantity(bytes))
+}
+
+// Build returns a new Resource with all types set in the builder. Changes made to the builder after the call do not
+// change the returned Resource.
+func (rb *ResourceBuilder) Build() *Resource {
+	return NewResourceFromMap(rb.resources).Clone()
+}
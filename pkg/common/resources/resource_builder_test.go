@@ -0,0 +1,73 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+)
+
+func TestResourceBuilder(t *testing.T) {
+	res := NewResourceBuilder().Build()
+	assert.Assert(t, res != nil && res.Resources != nil, "empty builder should build an empty resource")
+	assert.Equal(t, len(res.Resources), 0, "empty builder should build an empty resource")
+
+	res = NewResourceBuilder().WithCPU(1.5).WithMemory(1024).With("pods", 10).With("zero", 0).Build()
+	expected := NewResourceFromMap(map[string]Quantity{common.CPU: 1500, common.Memory: 1024, "pods": 10, "zero": 0})
+	assert.Assert(t, DeepEquals(res, expected), "unexpected resource: got %v, expected %v", res, expected)
+
+	// must match the units used when parsing the configuration
+	conf, err := NewResourceFromConf(map[string]string{common.CPU: "0.25", common.Memory: "1Mi"})
+	assert.NilError(t, err, "unexpected parse error")
+	res = NewResourceBuilder().WithCPU(0.25).WithMemory(1024 * 1024).Build()
+	assert.Assert(t, DeepEquals(res, conf), "builder and config differ: got %v, expected %v", res, conf)
+
+	for _, cores := range []string{"1.001", "0.57", "0.999", "12.345", "3"} {
+		conf, err = NewResourceFromConf(map[string]string{common.CPU: cores})
+		assert.NilError(t, err, "unexpected parse error")
+		value, err := strconv.ParseFloat(cores, 64)
+		assert.NilError(t, err, "unexpected float parse error")
+		res = NewResourceBuilder().WithCPU(value).Build()
+		assert.Assert(t, DeepEquals(res, conf), "builder and config differ for %s: got %v, expected %v", cores, res, conf)
+	}
+
+	// last value set wins
+	res = NewResourceBuilder().WithCPU(1).With(common.CPU, 5).Build()
+	assert.Equal(t, res.Resources[common.CPU], Quantity(5), "last value should be used")
+
+	// rounded and overflow protected
+	res = NewResourceBuilder().WithCPU(0.0014).Build()
+	assert.Equal(t, res.Resources[common.CPU], Quantity(1), "cpu should be rounded to the nearest millicore")
+	res = NewResourceBuilder().WithCPU(math.MaxInt64).Build()
+	assert.Equal(t, res.Resources[common.CPU], Quantity(math.MaxInt64), "cpu should not overflow")
+}
+
+func TestResourceBuilderBuildCopy(t *testing.T) {
+	builder := NewResourceBuilder().With("first", 1)
+	res := builder.Build()
+	builder.With("first", 2).With("second", 2)
+	assert.Assert(t, DeepEquals(res, NewResourceFromMap(map[string]Quantity{"first": 1})), "builder changes should not change a built resource")
+	res.Resources["first"] = 10
+	assert.Assert(t, DeepEquals(builder.Build(), NewResourceFromMap(map[string]Quantity{"first": 2, "second": 2})), "resource changes should not change the builder")
+}
@@ -0,0 +1,211 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+)
+
+// ResourceKind classifies a resource type which defines how quantities of that type are parsed and printed.
+type ResourceKind int
+
+const (
+	// DecimalSI quantities are parsed with binary or decimal SI suffixes and printed with decimal SI suffixes.
+	// This is the kind used for any resource type that has not been registered.
+	DecimalSI ResourceKind = iota
+	// Binary quantities are parsed with binary or decimal SI suffixes and printed with binary SI suffixes.
+	Binary
+	// Count quantities are whole numbers: parsed with binary or decimal SI suffixes, rejecting fractional values, and
+	// printed as plain integers.
+	Count
+	// Milli quantities are stored in thousandths of a unit and parsed using ParseMilliQuantity.
+	// Register this kind for resource types that need sub-integer precision, like fractional GPUs.
+	Milli
+)
+
+var kindRegistry = struct {
+	kinds map[string]ResourceKind
+
+	locking.RWMutex
+}{
+	kinds: map[string]ResourceKind{
+		common.CPU:    Milli,
+		common.Memory: Binary,
+		"pods":        Count,
+	},
+}
+
+// RegisterResourceKind sets the kind for the resource type. Registering a type again overwrites the earlier kind.
+// The kind is used when parsing a resource from a configuration and when printing a human-readable resource.
+func RegisterResourceKind(key string, kind ResourceKind) {
+	kindRegistry.Lock()
+	defer kindRegistry.Unlock()
+	kindRegistry.kinds[key] = kind
+}
+
+// GetResourceKind returns the kind registered for the resource type, DecimalSI if the type has not been registered.
+func GetResourceKind(key string) ResourceKind {
+	kindRegistry.RLock()
+	defer kindRegistry.RUnlock()
+	if kind, ok := kindRegistry.kinds[key]; ok {
+		return kind
+	}
+	return DecimalSI
+}
+
+// parseKind parses the value for the resource type based on the registered kind.
+func parseKind(key, value string) (Quantity, error) {
+	switch GetResourceKind(key) {
+	case Milli:
+		return ParseMilliQuantity(value)
+	case Count:
+		return ParseCount(value)
+	default:
+		return ParseQuantity(value)
+	}
+}
+
+var decimalSuffixes = []string{"E", "P", "T", "G", "M", "k"}
+var binarySuffixes = []string{"Ei", "Pi", "Ti", "Gi", "Mi", "Ki"}
+
+// formatKind formats the quantity using the suffixes for the kind. With zero digits the largest suffix that represents
+// the quantity exactly is used. Otherwise the largest suffix that is not larger than the quantity is used and the
+// quantity is rounded to the number of significant digits.
+func formatKind(kind ResourceKind, value Quantity, digits int) string {
+	switch kind {
+	case Count:
+		return value.string()
+	case Milli:
+		if value%1000 != 0 && (digits == 0 || absVal(value) < 1000) {
+			return value.string() + "m"
+		}
+		return formatScaled(value, 1000, decimalSuffixes, digits)
+	case Binary:
+		return formatScaled(value, 1, binarySuffixes, digits)
+	default:
+		return formatScaled(value, 1, decimalSuffixes, digits)
+	}
+}
+
+// formatScaled formats the quantity divided by the unit as described for formatKind.
+func formatScaled(value, unit Quantity, suffixes []string, digits int) string {
+	if digits == 0 {
+		return formatSuffix(value/unit, suffixes)
+	}
+	return formatSignificant(float64(value)/float64(unit), suffixes, digits)
+}
+
+func formatSuffix(value Quantity, suffixes []string) string {
+	if value != 0 {
+		for _, suffix := range suffixes {
+			scale := Quantity(multipliers[suffix])
+			if value%scale == 0 {
+				return strconv.FormatInt(int64(value/scale), 10) + suffix
+			}
+		}
+	}
+	return value.string()
+}
+
+// ToHumanString returns the resource as a human-readable string, with the quantity of each type formatted using the
+// registered kind of the type. The types are sorted by name: "map[memory:2Gi pods:110 vcore:1500m]".
+// A nil resource returns the same string as String does.
+func (r *Resource) ToHumanString() string {
+	if r == nil {
+		return r.String()
+	}
+	var sb strings.Builder
+	sb.WriteString("map[")
+	for i, k := range sortedKeys(r) {
+		if i > 0 {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(k + ":" + formatKind(GetResourceKind(k), r.Resources[k], 0))
+	}
+	sb.WriteString("]")
+	return sb.String()
+}
+
+// StringPrecision returns the resource in the same layout as MarshalText, with the quantity of each type rounded to
+// the given number of significant digits and using the largest suffix for the registered kind of the type, the same
+// suffixes as ToHumanString uses: "memory=4.00Gi,pods=110,vcore=1.50". Count types and quantities smaller than the
+// smallest suffix are written as plain integers. The rounding only affects the string, the resource is not changed.
+// A digit count smaller than 1 is handled as 1. A nil resource returns the same string as String does.
+func (r *Resource) StringPrecision(digits int) string {
+	if r == nil {
+		return r.String()
+	}
+	digits = max(1, digits)
+	return r.joinPairs(func(key string, value Quantity) string {
+		return formatKind(GetResourceKind(key), value, digits)
+	})
+}
+
+func formatSignificant(value float64, suffixes []string, digits int) string {
+	// the suffixes are ordered from large to small, an index past the end means no suffix
+	idx := len(suffixes)
+	for i, suffix := range suffixes {
+		if math.Abs(value) >= float64(multipliers[suffix]) {
+			idx = i
+			break
+		}
+	}
+	if idx == len(suffixes) && value == math.Trunc(value) {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	scaled := roundSignificant(value/suffixScale(suffixes, idx), digits)
+	// rounding can reach the next larger suffix: 1023.9996Ki must be shown as 1.00Mi not as 1024Ki
+	if idx > 0 && math.Abs(scaled)*suffixScale(suffixes, idx) >= suffixScale(suffixes, idx-1) {
+		idx--
+		scaled = roundSignificant(value/suffixScale(suffixes, idx), digits)
+	}
+	suffix := ""
+	if idx < len(suffixes) {
+		suffix = suffixes[idx]
+	}
+	return strconv.FormatFloat(scaled, 'f', significantDecimals(scaled, digits), 64) + suffix
+}
+
+// suffixScale returns the multiplier for the suffix at the index, 1 for an index past the end of the suffixes.
+func suffixScale(suffixes []string, idx int) float64 {
+	if idx >= len(suffixes) {
+		return 1
+	}
+	return float64(multipliers[suffixes[idx]])
+}
+
+// roundSignificant rounds the value to the number of significant digits, the integer part is never rounded.
+func roundSignificant(value float64, digits int) float64 {
+	pow := math.Pow10(significantDecimals(value, digits))
+	return math.Round(value*pow) / pow
+}
+
+// significantDecimals returns the number of decimals to show: significant digits beyond the integer digits.
+func significantDecimals(value float64, digits int) int {
+	intDigits := 1
+	for limit := 10.0; math.Abs(value) >= limit; limit *= 10 {
+		intDigits++
+	}
+	return max(0, digits-intDigits)
+}
@@ -0,0 +1,143 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+)
+
+func TestGetResourceKind(t *testing.T) {
+	assert.Equal(t, GetResourceKind(common.CPU), Milli, "unexpected kind for vcore")
+	assert.Equal(t, GetResourceKind(common.Memory), Binary, "unexpected kind for memory")
+	assert.Equal(t, GetResourceKind("pods"), Count, "unexpected kind for pods")
+	assert.Equal(t, GetResourceKind("unregistered"), DecimalSI, "unregistered type should be DecimalSI")
+
+	RegisterResourceKind("test.kind/gpu", Count)
+	defer RegisterResourceKind("test.kind/gpu", DecimalSI)
+	assert.Equal(t, GetResourceKind("test.kind/gpu"), Count, "registered kind not returned")
+	RegisterResourceKind("test.kind/gpu", Binary)
+	assert.Equal(t, GetResourceKind("test.kind/gpu"), Binary, "registered kind not overwritten")
+}
+
+func TestNewResourceFromConfKind(t *testing.T) {
+	RegisterResourceKind("test.kind/count", Count)
+	RegisterResourceKind("test.kind/milli", Milli)
+	defer func() {
+		RegisterResourceKind("test.kind/count", DecimalSI)
+		RegisterResourceKind("test.kind/milli", DecimalSI)
+	}()
+	tests := map[string]struct {
+		conf     map[string]string
+		expected map[string]Quantity
+		err      string
+	}{
+		"count":             {conf: map[string]string{"pods": "110", "test.kind/count": "2"}, expected: map[string]Quantity{"pods": 110, "test.kind/count": 2}},
+		"count suffix":      {conf: map[string]string{"pods": "1k"}, expected: map[string]Quantity{"pods": 1000}},
+		"count milli":       {conf: map[string]string{"pods": "500m"}, err: "invalid suffix"},
+		"count fraction":    {conf: map[string]string{"test.kind/count": "1.5"}, err: "fractional value not allowed"},
+		"milli":             {conf: map[string]string{"test.kind/milli": "1.5", common.CPU: "500m"}, expected: map[string]Quantity{"test.kind/milli": 1500, common.CPU: 500}},
+		"milli fraction":    {conf: map[string]string{"test.kind/milli": "0.5"}, expected: map[string]Quantity{"test.kind/milli": 500}},
+		"milli too precise": {conf: map[string]string{"test.kind/milli": "0.0005"}, err: "smaller than a millicore"},
+		"binary suffix":     {conf: map[string]string{common.Memory: "1Ki"}, expected: map[string]Quantity{common.Memory: 1024}},
+		"unregistered type": {conf: map[string]string{"other": "1k"}, expected: map[string]Quantity{"other": 1000}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := NewResourceFromConf(tt.conf)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err, "error expected")
+				return
+			}
+			assert.NilError(t, err, "no error expected")
+			assert.Assert(t, DeepEquals(res, NewResourceFromMap(tt.expected)), "unexpected resource: got %v, expected %v", res, tt.expected)
+		})
+	}
+}
+
+func TestToHumanString(t *testing.T) {
+	var nilRes *Resource
+	assert.Equal(t, nilRes.ToHumanString(), "nil resource")
+	assert.Equal(t, NewResource().ToHumanString(), "map[]")
+
+	tests := map[string]struct {
+		res      map[string]Quantity
+		expected string
+	}{
+		"count":             {res: map[string]Quantity{"pods": 1000}, expected: "map[pods:1000]"},
+		"milli":             {res: map[string]Quantity{common.CPU: 1500}, expected: "map[vcore:1500m]"},
+		"milli whole":       {res: map[string]Quantity{common.CPU: 2000}, expected: "map[vcore:2]"},
+		"milli large":       {res: map[string]Quantity{common.CPU: 4000000}, expected: "map[vcore:4k]"},
+		"binary":            {res: map[string]Quantity{common.Memory: 2 * 1024 * 1024 * 1024}, expected: "map[memory:2Gi]"},
+		"binary not exact":  {res: map[string]Quantity{common.Memory: 1000}, expected: "map[memory:1000]"},
+		"decimal":           {res: map[string]Quantity{"other": 3000000}, expected: "map[other:3M]"},
+		"zero values":       {res: map[string]Quantity{"other": 0, common.Memory: 0, common.CPU: 0}, expected: "map[memory:0 other:0 vcore:0]"},
+		"negative":          {res: map[string]Quantity{"other": -2000, common.CPU: -500}, expected: "map[other:-2k vcore:-500m]"},
+		"sorted types":      {res: map[string]Quantity{"pods": 110, common.Memory: 1024, common.CPU: 100}, expected: "map[memory:1Ki pods:110 vcore:100m]"},
+		"largest exact fit": {res: map[string]Quantity{common.Memory: 1536 * 1024}, expected: "map[memory:1536Ki]"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, NewResourceFromMap(tt.res).ToHumanString(), tt.expected)
+		})
+	}
+}
+
+func TestStringPrecision(t *testing.T) {
+	var nilRes *Resource
+	assert.Equal(t, nilRes.StringPrecision(3), "nil resource")
+	assert.Equal(t, NewResource().StringPrecision(3), "")
+
+	tests := map[string]struct {
+		res      map[string]Quantity
+		digits   int
+		expected string
+	}{
+		"binary exact":      {res: map[string]Quantity{common.Memory: 4 * 1024 * 1024 * 1024}, digits: 3, expected: "memory=4.00Gi"},
+		"binary rounded":    {res: map[string]Quantity{common.Memory: 1536*1024*1024 + 12345}, digits: 3, expected: "memory=1.50Gi"},
+		"binary large int":  {res: map[string]Quantity{common.Memory: 1000 * 1024 * 1024}, digits: 3, expected: "memory=1000Mi"},
+		"binary small":      {res: map[string]Quantity{common.Memory: 1000}, digits: 3, expected: "memory=1000"},
+		"count":             {res: map[string]Quantity{"pods": 123456}, digits: 2, expected: "pods=123456"},
+		"milli":             {res: map[string]Quantity{common.CPU: 1500}, digits: 3, expected: "vcore=1.50"},
+		"milli whole":       {res: map[string]Quantity{common.CPU: 2000}, digits: 3, expected: "vcore=2"},
+		"milli small":       {res: map[string]Quantity{common.CPU: 500}, digits: 3, expected: "vcore=500m"},
+		"decimal":           {res: map[string]Quantity{"other": 1234567}, digits: 2, expected: "other=1.2M"},
+		"negative":          {res: map[string]Quantity{"other": -2500}, digits: 2, expected: "other=-2.5k"},
+		"zero":              {res: map[string]Quantity{"other": 0}, digits: 3, expected: "other=0"},
+		"minimum digits":    {res: map[string]Quantity{"other": 1600}, digits: 0, expected: "other=2k"},
+		"binary boundary":   {res: map[string]Quantity{common.Memory: 1048575}, digits: 3, expected: "memory=1.00Mi"},
+		"decimal boundary":  {res: map[string]Quantity{"gpu": 999999}, digits: 3, expected: "gpu=1.00M"},
+		"milli boundary":    {res: map[string]Quantity{common.CPU: 999999}, digits: 3, expected: "vcore=1.00k"},
+		"milli unit bound":  {res: map[string]Quantity{common.CPU: 9999}, digits: 3, expected: "vcore=10.0"},
+		"digit boundary":    {res: map[string]Quantity{"other": 9999}, digits: 3, expected: "other=10.0k"},
+		"negative boundary": {res: map[string]Quantity{"other": -999999}, digits: 3, expected: "other=-1.00M"},
+		"below boundary":    {res: map[string]Quantity{"other": 999499}, digits: 3, expected: "other=999k"},
+		"sorted and joined": {res: map[string]Quantity{"pods": 110, common.Memory: 1024}, digits: 3, expected: "memory=1.00Ki,pods=110"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := NewResourceFromMap(tt.res)
+			assert.Equal(t, res.StringPrecision(tt.digits), tt.expected)
+			assert.DeepEqual(t, res.Resources, tt.res)
+		})
+	}
+}
@@ -19,18 +19,23 @@
 package resources
 
 import (
+	"cmp"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"math"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 
 	"github.com/apache/yunikorn-core/pkg/log"
-	"github.com/apache/yunikorn-scheduler-interface/lib/go/common"
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
@@ -63,6 +68,26 @@ func NewResourceFromProto(proto *si.Resource) *Resource {
 	return out
 }
 
+// NewResourceFromProtoFiltered creates a new resource from the proto only importing the types that are set to true
+// in the allowed map, other types are dropped during the conversion.
+// A nil allowed map imports all types, same as NewResourceFromProto. An empty allowed map imports nothing.
+// A nil proto returns an empty resource.
+func NewResourceFromProtoFiltered(proto *si.Resource, allowed map[string]bool) *Resource {
+	if allowed == nil {
+		return NewResourceFromProto(proto)
+	}
+	out := NewResource()
+	if proto == nil {
+		return out
+	}
+	for k, v := range proto.Resources {
+		if allowed[k] {
+			out.Resources[k] = Quantity(v.Value)
+		}
+	}
+	return out
+}
+
 func NewResourceFromMap(m map[string]Quantity) *Resource {
 	if m == nil {
 		return NewResource()
@@ -85,14 +110,7 @@ func NewResourceFromString(str string) (*Resource, error) {
 func NewResourceFromConf(configMap map[string]string) (*Resource, error) {
 	res := NewResource()
 	for key, strVal := range configMap {
-		var intValue Quantity
-		var err error
-		switch key {
-		case common.CPU:
-			intValue, err = ParseVCore(strVal)
-		default:
-			intValue, err = ParseQuantity(strVal)
-		}
+		intValue, err := parseKind(key, strVal)
 		if err != nil {
 			return nil, err
 		}
@@ -101,6 +119,16 @@ func NewResourceFromConf(configMap map[string]string) (*Resource, error) {
 	return res, nil
 }
 
+// NewResourceFromConfLimited creates a new resource from the config map in the same way as NewResourceFromConf.
+// The number of resource types in the config map is checked before anything is parsed or allocated, an error is
+// returned if the map contains more than maxTypes types. A maxTypes of zero or less only allows an empty map.
+func NewResourceFromConfLimited(configMap map[string]string, maxTypes int) (*Resource, error) {
+	if len(configMap) > max(0, maxTypes) {
+		return nil, fmt.Errorf("too many resource types: %d defined, maximum allowed %d", len(configMap), max(0, maxTypes))
+	}
+	return NewResourceFromConf(configMap)
+}
+
 func (r *Resource) String() string {
 	if r == nil {
 		return "nil resource"
@@ -108,6 +136,39 @@ func (r *Resource) String() string {
 	return fmt.Sprintf("%v", r.Resources)
 }
 
+// MarshalText implements the encoding.TextMarshaler interface.
+// The resource is written as a single line of comma separated key=value pairs sorted by key, for example:
+// "memory=1024,vcore=2000". A nil resource is written as "<nil>", an empty resource as an empty string.
+func (r *Resource) MarshalText() ([]byte, error) {
+	if r == nil {
+		return []byte("<nil>"), nil
+	}
+	return []byte(r.joinPairs(func(_ string, value Quantity) string {
+		return value.string()
+	})), nil
+}
+
+// joinPairs returns the comma separated key=value pairs sorted by key as written by MarshalText, with each quantity
+// formatted by the format function. The resource must not be nil.
+func (r *Resource) joinPairs(format func(key string, value Quantity) string) string {
+	var sb strings.Builder
+	for i, k := range sortedKeys(r) {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(k + "=" + format(k, r.Resources[k]))
+	}
+	return sb.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The resource is written as the plain struct: {"Resources":{"memory":1024}}. Without this the json encoder would
+// use MarshalText, which cannot be unmarshalled back into a resource.
+func (r *Resource) MarshalJSON() ([]byte, error) {
+	type plain Resource
+	return json.Marshal((*plain)(r))
+}
+
 func (r *Resource) DAOMap() map[string]int64 {
 	res := make(map[string]int64)
 	if r != nil {
@@ -118,6 +179,141 @@ func (r *Resource) DAOMap() map[string]int64 {
 	return res
 }
 
+// ToMapNonZero returns the same map as DAOMap without the types that have a zero quantity.
+// The resource is not changed, contrary to Prune the zero quantities are kept in the resource.
+// A nil resource returns an empty map.
+func (r *Resource) ToMapNonZero() map[string]int64 {
+	res := make(map[string]int64)
+	if r != nil {
+		for k, v := range r.Resources {
+			if v != 0 {
+				res[k] = int64(v)
+			}
+		}
+	}
+	return res
+}
+
+// ToMap returns a copy of the quantities of the resource.
+// Changes to the returned map do not change the resource.
+// A nil resource returns an empty map.
+func (r *Resource) ToMap() map[string]Quantity {
+	res := make(map[string]Quantity)
+	if r != nil {
+		for k, v := range r.Resources {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// Entry is a single resource type with its quantity.
+type Entry struct {
+	Type  string
+	Value Quantity
+}
+
+// Entries returns the quantities of the resource as a list of entries sorted by type.
+// A nil resource returns an empty list.
+func (r *Resource) Entries() []Entry {
+	keys := sortedKeys(r)
+	entries := make([]Entry, len(keys))
+	for i, k := range keys {
+		entries[i] = Entry{Type: k, Value: r.Resources[k]}
+	}
+	return entries
+}
+
+// FromEntries creates a new resource from the list of entries. The quantities of entries with the same type are
+// summed up, protected from overflow.
+// A nil or empty list returns an empty resource.
+func FromEntries(entries []Entry) *Resource {
+	out := NewResource()
+	for _, entry := range entries {
+		out.Resources[entry.Type] = addVal(out.Resources[entry.Type], entry.Value)
+	}
+	return out
+}
+
+// Iterate calls the function for each type defined in the resource, in sorted order of the type names. The iteration
+// stops as soon as the function returns false.
+// The function must not change the resource. A nil resource does not call the function.
+func (r *Resource) Iterate(fn func(key string, value Quantity) bool) {
+	for _, k := range sortedKeys(r) {
+		if !fn(k, r.Resources[k]) {
+			return
+		}
+	}
+}
+
+// GroupByPrefix partitions the types of the resource into groups keyed by the part of the type name before the first
+// separator: "hugepages-2Mi" and "hugepages-1Gi" are grouped under "hugepages" using "-" as the separator.
+// Types that do not contain the separator are grouped under the full type name. An empty separator groups each type
+// under the full type name.
+// A nil resource returns an empty map
+func (r *Resource) GroupByPrefix(separator string) map[string]*Resource {
+	groups := make(map[string]*Resource)
+	if r == nil {
+		return groups
+	}
+	for k, v := range r.Resources {
+		group := k
+		if separator != "" {
+			group, _, _ = strings.Cut(k, separator)
+		}
+		if _, ok := groups[group]; !ok {
+			groups[group] = NewResource()
+		}
+		groups[group].Resources[k] = v
+	}
+	return groups
+}
+
+// MergePolicy defines how quantities are combined when multiple types collapse into the same type name.
+type MergePolicy int
+
+const (
+	// MergePolicySum adds up the quantities of all colliding types.
+	MergePolicySum MergePolicy = iota
+	// MergePolicyMax keeps the largest quantity of all colliding types.
+	MergePolicyMax
+	// MergePolicyFirst keeps the quantity of the colliding type that sorts first by its original name.
+	MergePolicyFirst
+)
+
+// CoalesceAliases returns a new Resource with all type names replaced by their canonical name from the aliases map.
+// Types that are not in the aliases map keep their name. When multiple types map to the same canonical name the
+// policy decides how the quantities are combined. Types are processed in sorted order of their original names which
+// makes the result deterministic for all policies.
+// A nil resource returns nil
+func (r *Resource) CoalesceAliases(aliases map[string]string, policy MergePolicy) *Resource {
+	if r == nil {
+		return nil
+	}
+	out := NewResource()
+	for _, k := range sortedKeys(r) {
+		v := r.Resources[k]
+		canonical := k
+		if alias, ok := aliases[k]; ok {
+			canonical = alias
+		}
+		current, ok := out.Resources[canonical]
+		if !ok {
+			out.Resources[canonical] = v
+			continue
+		}
+		switch policy {
+		case MergePolicySum:
+			out.Resources[canonical] = addVal(current, v)
+		case MergePolicyMax:
+			out.Resources[canonical] = max(current, v)
+		case MergePolicyFirst:
+			// the quantity of the first type is already set
+		}
+	}
+	return out
+}
+
 // Convert to a protobuf implementation
 // a nil resource passes back an empty proto object
 func (r *Resource) ToProto() *si.Resource {
@@ -131,6 +327,179 @@ func (r *Resource) ToProto() *si.Resource {
 	return proto
 }
 
+// SliceToProto converts a slice of resources to a slice of protobuf implementations.
+// A nil resource in the slice passes back an empty proto object, a nil slice passes back a nil slice.
+func SliceToProto(resources []*Resource) []*si.Resource {
+	if resources == nil {
+		return nil
+	}
+	protos := make([]*si.Resource, len(resources))
+	for i, r := range resources {
+		protos[i] = r.ToProto()
+	}
+	return protos
+}
+
+// SliceFromProto converts a slice of protobuf implementations to a slice of resources.
+// A nil proto in the slice passes back an empty resource, a nil slice passes back a nil slice.
+func SliceFromProto(protos []*si.Resource) []*Resource {
+	if protos == nil {
+		return nil
+	}
+	resources := make([]*Resource, len(protos))
+	for i, proto := range protos {
+		resources[i] = NewResourceFromProto(proto)
+	}
+	return resources
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The resource is written as a flat mapping of resource type to quantity. Milli types, like CPU, are written in
+// thousandths using the 'm' suffix to allow round-tripping the value through UnmarshalYAML.
+// Negative quantities cannot be parsed by UnmarshalYAML and return an error.
+// Calling the method on a nil resource returns an empty mapping. The yaml encoder does not call the method for a nil
+// resource and writes it as null, which UnmarshalYAML leaves as a nil resource.
+func (r *Resource) MarshalYAML() (interface{}, error) {
+	out := make(map[string]interface{})
+	if r != nil {
+		for k, v := range r.Resources {
+			if v < 0 {
+				return nil, fmt.Errorf("invalid quantity for resource type %s: negative value %d", k, v)
+			}
+			if GetResourceKind(k) == Milli {
+				out[k] = v.string() + "m"
+			} else {
+				out[k] = int64(v)
+			}
+		}
+	}
+	return out, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The node must be a flat mapping of resource type to quantity. Quantities are parsed in the same way as
+// NewResourceFromConf does, including the unit suffixes.
+func (r *Resource) UnmarshalYAML(value *yaml.Node) error {
+	var configMap map[string]string
+	if err := value.Decode(&configMap); err != nil {
+		return err
+	}
+	res, err := NewResourceFromConf(configMap)
+	if err != nil {
+		return err
+	}
+	r.Resources = res.Resources
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+// The encoding is the same as used by MarshalBinary.
+// A nil resource is encoded as an empty resource.
+func (r *Resource) GobEncode() ([]byte, error) {
+	return encodeBinary(r), nil
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+// The data must have been created by GobEncode. Decoding always results in a resource with an allocated map, even
+// if no types are defined, replacing any existing types.
+func (r *Resource) GobDecode(data []byte) error {
+	res, err := decodeBinary(data)
+	if err != nil {
+		return err
+	}
+	r.Resources = res
+	return nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+// The encoding is a count of the types followed by the length prefixed name and the varint encoded quantity for
+// each type, types are written in sorted order.
+// A nil resource is encoded as zero length data, an empty resource as a zero count.
+func (r *Resource) MarshalBinary() ([]byte, error) {
+	if r == nil {
+		return []byte{}, nil
+	}
+	return encodeBinary(r), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// The data must have been created by MarshalBinary, replacing any existing types. Zero length data, the encoding of
+// a nil resource, results in a resource without an allocated map.
+func (r *Resource) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		r.Resources = nil
+		return nil
+	}
+	res, err := decodeBinary(data)
+	if err != nil {
+		return err
+	}
+	r.Resources = res
+	return nil
+}
+
+// encodeBinary writes the type count followed by (name length, name, quantity) for each type in sorted order.
+// A nil resource is encoded as an empty resource.
+func encodeBinary(r *Resource) []byte {
+	keys := sortedKeys(r)
+	buf := binary.AppendUvarint(nil, uint64(len(keys)))
+	for _, k := range keys {
+		buf = binary.AppendUvarint(buf, uint64(len(k)))
+		buf = append(buf, k...)
+		buf = binary.AppendVarint(buf, int64(r.Resources[k]))
+	}
+	return buf
+}
+
+// ShardKey returns a shard index in the range [0, numShards) based on a 32-bit hash of the sorted types and quantities
+// of the resource. Resources that are DeepEquals always return the same shard index. The hash is stable between
+// runs of the scheduler. A nil resource is hashed the same as an empty resource.
+// A numShards of zero or less always returns 0.
+func (r *Resource) ShardKey(numShards int) int {
+	if numShards <= 0 {
+		return 0
+	}
+	hash := fnv.New32a()
+	// writing to the hash never returns an error
+	_, _ = hash.Write(encodeBinary(r))
+	return int(uint64(hash.Sum32()) % uint64(numShards))
+}
+
+// decodeBinary reads the data as written by encodeBinary, always returns an allocated map if the data is valid.
+func decodeBinary(data []byte) (map[string]Quantity, error) {
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, errors.New("invalid resource encoding: type count")
+	}
+	data = data[n:]
+	// each type needs at least 2 bytes: prevents large allocations based on corrupt data
+	if count > uint64(len(data)/2) {
+		return nil, errors.New("invalid resource encoding: type count too large")
+	}
+	res := make(map[string]Quantity, count)
+	for i := uint64(0); i < count; i++ {
+		var keyLen uint64
+		keyLen, n = binary.Uvarint(data)
+		if n <= 0 || keyLen > uint64(len(data)-n) {
+			return nil, errors.New("invalid resource encoding: type name")
+		}
+		data = data[n:]
+		key := string(data[:keyLen])
+		data = data[keyLen:]
+		var value int64
+		value, n = binary.Varint(data)
+		if n <= 0 {
+			return nil, errors.New("invalid resource encoding: quantity for " + key)
+		}
+		data = data[n:]
+		res[key] = Quantity(value)
+	}
+	if len(data) != 0 {
+		return nil, errors.New("invalid resource encoding: unexpected trailing data")
+	}
+	return res, nil
+}
+
 // Clone returns a clone (copy) of the resource it is called on.
 // This provides a deep copy of the object with the exact same member set.
 // NOTE: this is a clone not a sparse copy of the original.
@@ -158,6 +527,46 @@ func (r *Resource) Prune() {
 	}
 }
 
+// PruneReporting removes any resource type that has a zero value set, same as Prune, and returns the sorted list of
+// the removed types.
+// A nil resource does not change and returns an empty list
+func (r *Resource) PruneReporting() []string {
+	removed := make([]string, 0)
+	if r == nil {
+		return removed
+	}
+	for k, v := range r.Resources {
+		if v == 0 {
+			delete(r.Resources, k)
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// Set sets the quantity for the resource type on the resource it is called on.
+// The map is allocated if the resource was not created via NewResource.
+// A nil resource does not change
+func (r *Resource) Set(key string, value Quantity) {
+	if r == nil {
+		return
+	}
+	if r.Resources == nil {
+		r.Resources = make(map[string]Quantity)
+	}
+	r.Resources[key] = value
+}
+
+// Remove removes the resource type from the resource it is called on.
+// A nil resource does not change
+func (r *Resource) Remove(key string) {
+	if r == nil {
+		return
+	}
+	delete(r.Resources, key)
+}
+
 // AddTo adds the resource to the base updating the base resource
 // Should be used by temporary computation only
 // A nil base resource does not change
@@ -173,6 +582,36 @@ func (r *Resource) AddTo(add *Resource) {
 	}
 }
 
+// ApplyDelta adds the delta to the base updating the base resource, the same way AddTo does.
+// Returns true if at least one quantity in the base changed. Adding a zero quantity for a type that is not defined in
+// the base adds the type, but the quantity does not change as an undefined quantity is assumed zero.
+// A nil base resource does not change and returns false.
+// A nil delta is treated as a zero valued resource, leaves base unchanged and returns false.
+func (r *Resource) ApplyDelta(delta *Resource) bool {
+	if r == nil || delta == nil {
+		return false
+	}
+	changed := false
+	for k, v := range delta.Resources {
+		oldVal := r.Resources[k]
+		newVal := addVal(oldVal, v)
+		r.Resources[k] = newVal
+		changed = changed || newVal != oldVal
+	}
+	return changed
+}
+
+// DrainInto adds all quantities of the resource to the destination and removes all types from the resource.
+// The additions are protected from overflow.
+// A nil resource or destination does not change anything, draining a resource into itself does not change the resource.
+func (r *Resource) DrainInto(dst *Resource) {
+	if r == nil || dst == nil || r == dst {
+		return
+	}
+	dst.AddTo(r)
+	clear(r.Resources)
+}
+
 // Subtract from the resource the passed in resource by updating the resource it is called on.
 // Should be used by temporary computation only
 // A nil base resource does not change
@@ -198,6 +637,60 @@ func (r *Resource) MultiplyTo(ratio float64) {
 	}
 }
 
+// NormalizeShape returns a new resource with all positive quantities divided by their greatest common divisor.
+// Resources with the same shape, independent of the scale, have the same normalized shape.
+// Zero and negative quantities are not changed and not used in the divisor calculation.
+// A nil resource returns nil
+func (r *Resource) NormalizeShape() *Resource {
+	if r == nil {
+		return nil
+	}
+	var divisor Quantity
+	for _, v := range r.Resources {
+		if v > 0 {
+			divisor = gcd(divisor, v)
+		}
+	}
+	ret := r.Clone()
+	if divisor <= 1 {
+		return ret
+	}
+	for k, v := range ret.Resources {
+		if v > 0 {
+			ret.Resources[k] = v / divisor
+		}
+	}
+	return ret
+}
+
+// SameShape returns true if the resources have the same shape: a positive scalar k exists for which left = k * right
+// for all types. Both resources must define the same set of types, a zero quantity in one resource must be matched by
+// a zero quantity in the other resource and the signs of the quantities must match.
+// The shapes are compared using NormalizeShape on the absolute values of the quantities.
+// A nil resource is treated as an empty resource, two empty resources have the same shape.
+func SameShape(left, right *Resource) bool {
+	if left == nil {
+		left = Zero // shadows in the local function not seen by the callers.
+	}
+	if right == nil {
+		right = Zero // shadows in the local function not seen by the callers.
+	}
+	if len(left.Resources) != len(right.Resources) {
+		return false
+	}
+	lAbs := NewResource()
+	rAbs := NewResource()
+	for k, lVal := range left.Resources {
+		rVal, ok := right.Resources[k]
+		if !ok || cmp.Compare(lVal, 0) != cmp.Compare(rVal, 0) {
+			return false
+		}
+		lAbs.Resources[k] = absVal(lVal)
+		rAbs.Resources[k] = absVal(rVal)
+	}
+	return DeepEquals(lAbs.NormalizeShape(), rAbs.NormalizeShape())
+}
+
 // Calculate how well the receiver fits in "fit"
 //   - A score of 0 is a fit (similar to FitIn)
 //   - The score is calculated only using resource type defined in the fit resource.
@@ -239,6 +732,232 @@ func (r *Resource) FitInScore(fit *Resource) float64 {
 	return score
 }
 
+// FitInScoreNormalized calculates the same score as FitInScore divided by the number of types in fit with a positive
+// quantity. The score is in the range 0 to 1 independent of the number of types: 0 is a perfect fit.
+// An empty or nil fit, or a fit without any positive quantity, returns 0.
+// A nil receiver gives back the maximum score of 1.
+func (r *Resource) FitInScoreNormalized(fit *Resource) float64 {
+	count := fit.PositiveCount()
+	if count == 0 {
+		return 0
+	}
+	// a nil receiver counts all fit types, also the non-positive ones: cap the score
+	return min(1, r.FitInScore(fit)/float64(count))
+}
+
+// ClosestFit returns the index and score of the candidate the request fits in with the lowest FitInScore.
+// Candidates the request does not fit in are skipped. If multiple candidates have the same score the first one
+// in the list is returned.
+// If the request does not fit in any of the candidates the index returned is -1 with a score of 0.
+func ClosestFit(request *Resource, candidates []*Resource) (int, float64) {
+	index := -1
+	var best float64
+	for i, candidate := range candidates {
+		if !candidate.FitIn(request) {
+			continue
+		}
+		score := request.FitInScore(candidate)
+		if index == -1 || score < best {
+			index = i
+			best = score
+		}
+	}
+	return index, best
+}
+
+// FitsAny returns true if the request fits in at least one of the capacities, using the FitIn semantics.
+// An empty or nil list of capacities returns false.
+func FitsAny(request *Resource, capacities []*Resource) bool {
+	for _, capacity := range capacities {
+		if capacity.FitIn(request) {
+			return true
+		}
+	}
+	return false
+}
+
+// FitsAll returns true if the request fits in all of the capacities, using the FitIn semantics.
+// An empty or nil list of capacities returns true.
+func FitsAll(request *Resource, capacities []*Resource) bool {
+	for _, capacity := range capacities {
+		if !capacity.FitIn(request) {
+			return false
+		}
+	}
+	return true
+}
+
+// WeightedSum collapses the resource into a single value: the sum of the quantity multiplied by the weight for
+// each type. Types that have no weight listed do not contribute to the sum, the weight does not default to 1 as it
+// does in the node sorting policy.
+// A nil resource returns 0
+func (r *Resource) WeightedSum(weights map[string]float64) float64 {
+	var sum float64
+	if r == nil {
+		return sum
+	}
+	for k, v := range r.Resources {
+		sum += float64(v) * weights[k]
+	}
+	return sum
+}
+
+// L1Norm returns the sum of the absolute values of all quantities in the resource.
+// Result is protected from overflow.
+// A nil resource returns 0
+func (r *Resource) L1Norm() int64 {
+	var norm Quantity
+	if r == nil {
+		return int64(norm)
+	}
+	for _, v := range r.Resources {
+		norm = addVal(norm, absVal(v))
+	}
+	return int64(norm)
+}
+
+// Composition returns the fraction of the L1Norm each type contributes: the absolute value of the quantity divided
+// by the sum of the absolute values of all quantities. The fractions of all types add up to 1.
+// The sum is calculated as a float, unlike L1Norm, to make sure the fractions are correct for very large values.
+// A nil, empty or all zero resource returns an empty map.
+func (r *Resource) Composition() map[string]float64 {
+	composition := make(map[string]float64)
+	if r == nil {
+		return composition
+	}
+	var norm float64
+	for _, v := range r.Resources {
+		norm += float64(absVal(v))
+	}
+	if norm == 0 {
+		return composition
+	}
+	for k, v := range r.Resources {
+		composition[k] = float64(absVal(v)) / norm
+	}
+	return composition
+}
+
+// MaxComponent returns the type and quantity of the type with the largest absolute value in the resource.
+// The quantity is returned as defined in the resource, including the sign. If multiple types have the same
+// absolute value the first type in alphabetical order is returned.
+// The boolean is false for a nil or empty resource, the type is then empty and the quantity zero.
+func (r *Resource) MaxComponent() (string, Quantity, bool) {
+	var name string
+	var value Quantity
+	found := false
+	for _, k := range sortedKeys(r) {
+		v := r.Resources[k]
+		if !found || absDiff(v, 0) > absDiff(value, 0) {
+			name = k
+			value = v
+			found = true
+		}
+	}
+	return name, value, found
+}
+
+// Max returns the largest quantity defined in the resource, independent of the type.
+// A nil or empty resource returns 0, this cannot be distinguished from a resource with a largest quantity of 0.
+func (r *Resource) Max() Quantity {
+	return r.extreme(func(a, b Quantity) bool { return a > b })
+}
+
+// Min returns the smallest quantity defined in the resource, independent of the type.
+// A nil or empty resource returns 0, this cannot be distinguished from a resource with a smallest quantity of 0.
+func (r *Resource) Min() Quantity {
+	return r.extreme(func(a, b Quantity) bool { return a < b })
+}
+
+// extreme returns the quantity for which the function returns true when compared to all other quantities.
+// A nil or empty resource returns 0
+func (r *Resource) extreme(better func(a, b Quantity) bool) Quantity {
+	var result Quantity
+	if r == nil {
+		return result
+	}
+	first := true
+	for _, v := range r.Resources {
+		if first || better(v, result) {
+			result = v
+			first = false
+		}
+	}
+	return result
+}
+
+// sortedKeys returns the resource types defined in the resource sorted by name.
+// A nil resource returns an empty slice.
+func sortedKeys(r *Resource) []string {
+	if r == nil {
+		return []string{}
+	}
+	keys := make([]string, 0, len(r.Resources))
+	for k := range r.Resources {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// gcd returns the greatest common divisor of two non-negative quantities using the Euclidean algorithm.
+// The gcd of 0 and a value is the value itself.
+func gcd(valA, valB Quantity) Quantity {
+	for valB != 0 {
+		valA, valB = valB, valA%valB
+	}
+	return valA
+}
+
+// absDiff returns the absolute difference between two quantities.
+// The result is unsigned to prevent wrapping for any combination of values.
+func absDiff(valA, valB Quantity) uint64 {
+	if valA > valB {
+		return uint64(valA) - uint64(valB)
+	}
+	return uint64(valB) - uint64(valA)
+}
+
+// absVal returns the absolute value of the quantity.
+// The absolute value of the minimum quantity cannot be represented and is clamped to the maximum quantity.
+func absVal(val Quantity) Quantity {
+	switch {
+	case val == math.MinInt64:
+		return math.MaxInt64
+	case val < 0:
+		return -val
+	default:
+		return val
+	}
+}
+
+// OverflowHook is called with the operation and the input values each time a calculation on quantities wrapped and
+// the result was clamped to the minimum or maximum value possible. The ratio is only set for the operations that use a
+// float factor, it is zero for the integer operations.
+type OverflowHook func(op string, valA, valB Quantity, ratio float64)
+
+var overflowHook atomic.Pointer[OverflowHook]
+
+// SetOverflowHook sets the hook that is called each time a calculation on quantities is clamped. The hook is called
+// in addition to the warning being logged. Subtraction is reported as an "add" operation with the negated value.
+// The ratio based multiplications pass the value and the ratio, with valB set to zero. An interpolation passes the start and
+// end value with the interpolation factor as the ratio.
+// Passing nil removes the hook, which is the default.
+func SetOverflowHook(hook OverflowHook) {
+	if hook == nil {
+		overflowHook.Store(nil)
+		return
+	}
+	overflowHook.Store(&hook)
+}
+
+// notifyOverflow calls the overflow hook if one has been set.
+func notifyOverflow(op string, valA, valB Quantity, ratio float64) {
+	if hook := overflowHook.Load(); hook != nil {
+		(*hook)(op, valA, valB, ratio)
+	}
+}
+
 // Wrapping safe calculators for the quantities of resources.
 // They will always return a valid int64. Logging if the calculator wrapped the value.
 // Returning the appropriate MaxInt64 or MinInt64 value.
@@ -251,12 +970,14 @@ func addVal(valA, valB Quantity) Quantity {
 			log.Log(log.Resources).Warn("Resource calculation wrapped: returned minimum value possible",
 				zap.Int64("valueA", int64(valA)),
 				zap.Int64("valueB", int64(valB)))
+			notifyOverflow("add", valA, valB, 0)
 			return math.MinInt64
 		}
 		// return the maximum possible
 		log.Log(log.Resources).Warn("Resource calculation wrapped: returned maximum value possible",
 			zap.Int64("valueA", int64(valA)),
 			zap.Int64("valueB", int64(valB)))
+		notifyOverflow("add", valA, valB, 0)
 		return math.MaxInt64
 	}
 	// not wrapped normal case
@@ -272,39 +993,63 @@ func mulVal(valA, valB Quantity) Quantity {
 	if valA == 0 || valB == 0 {
 		return 0
 	}
-	result := valA * valB
-	// check the wrapping
-	// MinInt64 * -1 is special: it returns MinInt64, it should return MaxInt64 but does not trigger
-	// wrapping if not specially checked
-	if (result/valB != valA) || (valA == math.MinInt64 && valB == -1) {
+	if mulWraps(valA, valB) {
 		if (valA < 0) != (valB < 0) {
 			// return the minimum possible
 			log.Log(log.Resources).Warn("Resource calculation wrapped: returned minimum value possible",
 				zap.Int64("valueA", int64(valA)),
 				zap.Int64("valueB", int64(valB)))
+			notifyOverflow("multiply", valA, valB, 0)
 			return math.MinInt64
 		}
 		// return the maximum possible
 		log.Log(log.Resources).Warn("Resource calculation wrapped: returned maximum value possible",
 			zap.Int64("valueA", int64(valA)),
 			zap.Int64("valueB", int64(valB)))
+		notifyOverflow("multiply", valA, valB, 0)
 		return math.MaxInt64
 	}
 	// not wrapped normal case
-	return result
+	return valA * valB
+}
+
+// mulWraps returns true if the multiplication of the two quantities wraps.
+func mulWraps(valA, valB Quantity) bool {
+	if valA == 0 || valB == 0 {
+		return false
+	}
+	result := valA * valB
+	// MinInt64 * -1 is special: it returns MinInt64, it should return MaxInt64 but does not trigger
+	// wrapping if not specially checked
+	return (result/valB != valA) || (valA == math.MinInt64 && valB == -1)
 }
 
 func mulValRatio(value Quantity, ratio float64) Quantity {
+	return mulValRatioWith(value, ratio, math.Trunc, "multiplyRatio")
+}
+
+func mulValRatioCeil(value Quantity, ratio float64) Quantity {
+	return mulValRatioWith(value, ratio, math.Ceil, "multiplyRatioCeil")
+}
+
+func mulValRatioRound(value Quantity, ratio float64) Quantity {
+	return mulValRatioWith(value, ratio, math.Round, "multiplyRatioRound")
+}
+
+// mulValRatioWith multiplies the value with the ratio and converts the result back to a quantity using the round
+// function. The operation is used to report the overflow if the result is clamped.
+func mulValRatioWith(value Quantity, ratio float64, round func(float64) float64, op string) Quantity {
 	// optimise the zero cases (often hit with zero resource)
 	if value == 0 || ratio == 0 {
 		return 0
 	}
-	result := float64(value) * ratio
-	// protect against positive integer overflow
-	if result > math.MaxInt64 {
+	result := round(float64(value) * ratio)
+	// protect against positive integer overflow: MaxInt64 as a float is rounded up to 2^63
+	if result >= math.MaxInt64 {
 		log.Log(log.Resources).Warn("Multiplication result positive overflow",
 			zap.Float64("value", float64(value)),
 			zap.Float64("ratio", ratio))
+		notifyOverflow(op, value, 0, ratio)
 		return math.MaxInt64
 	}
 	// protect against negative integer overflow
@@ -312,6 +1057,7 @@ func mulValRatio(value Quantity, ratio float64) Quantity {
 		log.Log(log.Resources).Warn("Multiplication result negative overflow",
 			zap.Float64("value", float64(value)),
 			zap.Float64("ratio", ratio))
+		notifyOverflow(op, value, 0, ratio)
 		return math.MinInt64
 	}
 	// not wrapped normal case
@@ -364,6 +1110,59 @@ func Sub(left, right *Resource) *Resource {
 	return out
 }
 
+// AddMany adds all resources returning a new resource with the result
+// A nil resource is considered an empty resource
+// No resources passed in returns a new empty resource (zero)
+func AddMany(resources ...*Resource) *Resource {
+	out := NewResource()
+	for _, res := range resources {
+		if res == nil {
+			continue
+		}
+		for k, v := range res.Resources {
+			out.Resources[k] = addVal(out.Resources[k], v)
+		}
+	}
+	return out
+}
+
+// AddCapped adds resources returning a new resource with the result capped at the ceiling: min(base+add, ceiling) for
+// each type in the union of base and add. Types not defined in the ceiling are not capped.
+// The addition is protected from overflow before the cap is applied.
+// A nil resource is considered an empty resource, a nil ceiling does not cap any type.
+func AddCapped(base, add, ceiling *Resource) *Resource {
+	out := Add(base, add)
+	if ceiling == nil {
+		return out
+	}
+	for k, v := range out.Resources {
+		if ceilVal, ok := ceiling.Resources[k]; ok {
+			out.Resources[k] = min(v, ceilVal)
+		}
+	}
+	return out
+}
+
+// SubMany subtracts all resources from the base returning a new resource with the result
+// A nil resource is considered an empty resource
+// This might return negative values for specific quantities
+func SubMany(base *Resource, subtract ...*Resource) *Resource {
+	// check nil inputs and shortcut
+	if base == nil {
+		base = Zero
+	}
+	out := base.Clone()
+	for _, res := range subtract {
+		if res == nil {
+			continue
+		}
+		for k, v := range res.Resources {
+			out.Resources[k] = subVal(out.Resources[k], v)
+		}
+	}
+	return out
+}
+
 // SubOnlyExisting subtracts delta from base resource, ignoring any type not defined in the base resource.
 func SubOnlyExisting(base, delta *Resource) *Resource {
 	// check nil inputs and shortcut
@@ -400,6 +1199,59 @@ func SubEliminateNegative(left, right *Resource) *Resource {
 	return res
 }
 
+// SubWithFloor subtracts resource returning a new resource with the result floored at the per type minimum
+// defined in the floor. The result only contains the types defined in left, types only defined in right are ignored.
+// A nil resource is considered an empty resource, a nil floor or a type not defined in the floor floors at zero.
+// With a nil floor this is SubEliminateNegative restricted to the types defined in left.
+func SubWithFloor(left, right, floor *Resource) *Resource {
+	out := NewResource()
+	if left == nil {
+		return out
+	}
+	for k, v := range left.Resources {
+		var rightVal, floorVal Quantity
+		if right != nil {
+			rightVal = right.Resources[k]
+		}
+		if floor != nil {
+			floorVal = floor.Resources[k]
+		}
+		out.Resources[k] = max(subVal(v, rightVal), floorVal)
+	}
+	return out
+}
+
+// Free returns the resources left in the capacity after the used resources are removed, only for the types
+// defined in the capacity. Types only defined in used are ignored and no value drops below zero.
+// A nil capacity returns an empty resource, a nil used resource returns a clone of the capacity with negative
+// values reset to zero.
+func (r *Resource) Free(used *Resource) *Resource {
+	return SubWithFloor(r, used, nil)
+}
+
+// Excess returns the amount by which the resource exceeds the limit, only for the types that exceed the limit.
+// Types within the limit are not part of the result. This is the reverse of Free: Free reports what is left for the
+// types defined in the capacity, Excess reports what is over for the types defined in the resource.
+// A type not defined in the limit is considered 0 for Quantity, any positive quantity of that type is excess.
+// A nil resource returns an empty resource, a nil limit is considered an empty resource.
+// Result is protected from overflow.
+func (r *Resource) Excess(limit *Resource) *Resource {
+	out := NewResource()
+	if r == nil {
+		return out
+	}
+	for k, v := range r.Resources {
+		var limitVal Quantity
+		if limit != nil {
+			limitVal = limit.Resources[k]
+		}
+		if v > limitVal {
+			out.Resources[k] = subVal(v, limitVal)
+		}
+	}
+	return out
+}
+
 // SubErrorNegative subtracts resource returning a new resource with the result. A nil resource is considered
 // an empty resource. This will return an error if any value in the result is negative.
 // The caller should at least log the error.
@@ -442,6 +1294,62 @@ func subNonNegative(left, right *Resource) (*Resource, string) {
 	return out, message
 }
 
+// Diff compares the old and new resource returning three new resources describing the changes:
+// added contains the types only defined in the new resource with the new value
+// removed contains the types only defined in the old resource with the old value
+// changed contains the types defined in both with different values, the value is the delta: new minus old
+// A nil resource is considered an empty resource, the passed in resources are not changed.
+func Diff(oldRes, newRes *Resource) (added, removed, changed *Resource) {
+	added = NewResource()
+	removed = NewResource()
+	changed = NewResource()
+	if oldRes == nil {
+		oldRes = Zero
+	}
+	if newRes == nil {
+		newRes = Zero
+	}
+	for k, v := range newRes.Resources {
+		oldVal, ok := oldRes.Resources[k]
+		switch {
+		case !ok:
+			added.Resources[k] = v
+		case oldVal != v:
+			changed.Resources[k] = subVal(v, oldVal)
+		}
+	}
+	for k, v := range oldRes.Resources {
+		if _, ok := newRes.Resources[k]; !ok {
+			removed.Resources[k] = v
+		}
+	}
+	return added, removed, changed
+}
+
+// ChangeEvent returns the signed delta, new minus old, for each type that changed between the old and new resource,
+// and true if any type changed. Types with the same value in both resources are omitted.
+// A type not defined in one of the resources is considered 0 for Quantity: a type that is only defined with a zero
+// value is not a change.
+// A nil resource is considered an empty resource, the passed in resources are not changed.
+func ChangeEvent(oldRes, newRes *Resource) (map[string]int64, bool) {
+	added, removed, changed := Diff(oldRes, newRes)
+	deltas := make(map[string]int64)
+	for k, v := range added.Resources {
+		if v != 0 {
+			deltas[k] = int64(v)
+		}
+	}
+	for k, v := range removed.Resources {
+		if v != 0 {
+			deltas[k] = int64(subVal(0, v))
+		}
+	}
+	for k, v := range changed.Resources {
+		deltas[k] = int64(v)
+	}
+	return deltas, len(deltas) > 0
+}
+
 // FitIn checks if smaller fits in the defined resource
 // Types not defined in resource this is called against are considered 0 for Quantity
 // A nil resource is treated as an empty resource (no types defined)
@@ -456,6 +1364,127 @@ func (r *Resource) FitInMaxUndef(smaller *Resource) bool {
 	return r.fitIn(smaller, true)
 }
 
+// FitInWithBuffer checks if the request fits in the defined resource after the buffer is removed from it, using the
+// same rules as FitIn. The buffer is subtracted per type and the result is floored at zero, buffer types not defined
+// in the resource this is called against are ignored.
+// A nil buffer is treated as an empty resource and behaves the same as FitIn.
+// A nil resource is treated as an empty resource (no types defined)
+func (r *Resource) FitInWithBuffer(request, buffer *Resource) bool {
+	if buffer == nil {
+		return r.FitIn(request)
+	}
+	return SubWithFloor(r, buffer, nil).FitIn(request)
+}
+
+// FitInIgnoringMissing checks if smaller fits in the defined resource, same as FitIn, while skipping the types listed
+// in ignore. The ignored types are not checked at all: they never cause the check to fail, independent of being
+// defined in either resource or their values.
+// Types not defined in resource this is called against are considered 0 for Quantity
+// A nil resource is treated as an empty resource (no types defined)
+func (r *Resource) FitInIgnoringMissing(smaller *Resource, ignore []string) bool {
+	if r == nil {
+		r = Zero // shadows in the local function not seen by the callers.
+	}
+	if smaller == nil {
+		return true
+	}
+	skip := make(map[string]bool, len(ignore))
+	for _, k := range ignore {
+		skip[k] = true
+	}
+	for k, v := range smaller.Resources {
+		if !skip[k] && !fitsIn(v, r.Resources[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+// FitInNonZeroCapacity checks if the resource fits in the capacity, only checking the types that have a positive
+// quantity in the capacity. Capacity types with a zero or negative quantity are considered unconstrained instead of
+// having no headroom. In the root queue a capacity type can be 0 while there is still usage, when the last node that
+// reported the type was removed but not everything has been updated yet.
+// Types not defined in the capacity are considered 0 for Quantity, and are thus also unconstrained.
+// A nil resource always fits, a nil capacity is treated as an empty resource (no types defined)
+func (r *Resource) FitInNonZeroCapacity(capacity *Resource) bool {
+	if r == nil || capacity == nil {
+		return true
+	}
+	for k, v := range r.Resources {
+		if capVal := capacity.Resources[k]; capVal > 0 && !fitsIn(v, capVal) {
+			return false
+		}
+	}
+	return true
+}
+
+// FitInDetailed checks if smaller fits in the defined resource, same as FitIn, and returns the sorted list of
+// types for which smaller does not fit.
+// Types not defined in resource this is called against are considered 0 for Quantity
+// A nil resource is treated as an empty resource (no types defined)
+func (r *Resource) FitInDetailed(smaller *Resource) (bool, []string) {
+	fits, failures := r.FitInExplain(smaller)
+	failed := make([]string, len(failures))
+	for i, failure := range failures {
+		failed[i] = failure.Type
+	}
+	return fits, failed
+}
+
+// FitFailure describes a single resource type for which a request does not fit.
+// The available quantity is never negative: negative values are treated as 0, the same as FitIn does.
+type FitFailure struct {
+	Type      string
+	Available Quantity
+	Requested Quantity
+	Deficit   Quantity
+}
+
+// FitInExplain checks if the request fits in the defined resource, same as FitIn, and returns a failure for each type
+// for which the request does not fit sorted by type. The deficit is the requested minus the available quantity.
+// Types not defined in resource this is called against are considered 0 for Quantity
+// A nil resource is treated as an empty resource (no types defined)
+func (r *Resource) FitInExplain(request *Resource) (bool, []FitFailure) {
+	failures := make([]FitFailure, 0)
+	if r == nil {
+		r = Zero // shadows in the local function not seen by the callers.
+	}
+	for _, k := range sortedKeys(request) {
+		requested := request.Resources[k]
+		available := max(0, r.Resources[k])
+		if !fitsIn(requested, available) {
+			failures = append(failures, FitFailure{
+				Type:      k,
+				Available: available,
+				Requested: requested,
+				Deficit:   subVal(requested, available),
+			})
+		}
+	}
+	return len(failures) == 0, failures
+}
+
+// LargestDeficit returns the type and amount of the largest shortage when the request is compared to the available
+// resource: the type for which the requested quantity minus the available quantity is the largest positive value.
+// Types not defined in the available resource are considered 0, negative available values will be treated as 0, the
+// same as FitIn does. If multiple types have the same deficit the first type in alphabetical order is returned.
+// If the request fits an empty type with a zero deficit is returned.
+func LargestDeficit(available, request *Resource) (string, Quantity) {
+	if available == nil {
+		available = Zero // shadows in the local function not seen by the callers.
+	}
+	var name string
+	var deficit Quantity
+	for _, k := range sortedKeys(request) {
+		short := subVal(request.Resources[k], max(0, available.Resources[k]))
+		if short > deficit {
+			name = k
+			deficit = short
+		}
+	}
+	return name, deficit
+}
+
 // Check if smaller fits in the defined resource
 // Negative values will be treated as 0
 // A nil resource is treated as an empty resource, behaviour defined by skipUndef
@@ -469,18 +1498,154 @@ func (r *Resource) fitIn(smaller *Resource, skipUndef bool) bool {
 		return true
 	}
 
-	for k, v := range smaller.Resources {
-		largerValue, ok := r.Resources[k]
-		// skip if not defined (queue quota checks: undefined resources are considered max)
-		if skipUndef && !ok {
-			continue
+	for k, v := range smaller.Resources {
+		largerValue, ok := r.Resources[k]
+		// skip if not defined (queue quota checks: undefined resources are considered max)
+		if skipUndef && !ok {
+			continue
+		}
+		if !fitsIn(v, largerValue) {
+			return false
+		}
+	}
+	return true
+}
+
+// fitsIn returns true if the requested quantity fits in the available quantity.
+// This defines a fit for all FitIn variants: a negative available quantity is treated as 0.
+func fitsIn(requested, available Quantity) bool {
+	return requested <= max(0, available)
+}
+
+// FitInRatio returns the fraction of the request that fits in the defined resource based on the type that is the
+// tightest fit: the minimum of the defined quantity divided by the requested quantity over all requested types.
+// The ratio is capped at 1, which means the request fits. Requested types with a zero or negative quantity always fit.
+// Types not defined in the resource this is called against are considered 0, negative values will be treated as 0.
+// A nil resource is treated as an empty resource (no types defined), a nil request always fits.
+func (r *Resource) FitInRatio(request *Resource) float64 {
+	if r == nil {
+		r = Zero // shadows in the local function not seen by the callers.
+	}
+	ratio := 1.0
+	if request == nil {
+		return ratio
+	}
+	for k, v := range request.Resources {
+		if v <= 0 {
+			continue
+		}
+		ratio = min(ratio, float64(max(0, r.Resources[k]))/float64(v))
+	}
+	return ratio
+}
+
+// ScaleToFit scales the request down, preserving the proportions between the types, so that it fits in the available
+// resource. Returns the scaled resource and the ratio used for scaling, the ratio is never larger than 1.
+// A request that fits is returned as a clone with a ratio of 1.
+// If a type in the request has a zero (or negative) available quantity an empty resource and a ratio of 0 is returned.
+// A nil available resource is treated as an empty resource (no types defined), same as FitIn.
+func ScaleToFit(request, available *Resource) (*Resource, float64) {
+	if request == nil {
+		return NewResource(), 1.0
+	}
+	if available == nil {
+		available = Zero
+	}
+	ratio := 1.0
+	for k, v := range request.Resources {
+		// negative or zero requests always fit
+		if v <= 0 {
+			continue
+		}
+		availValue := max(0, available.Resources[k])
+		if availValue == 0 {
+			return NewResource(), 0.0
+		}
+		if v > availValue {
+			ratio = min(ratio, float64(availValue)/float64(v))
+		}
+	}
+	if ratio == 1.0 {
+		return request.Clone(), ratio
+	}
+	return MultiplyBy(request, ratio), ratio
+}
+
+// FitInCount returns the number of complete copies of the request that fit in the capacity when the used resources
+// are taken into account: the minimum of the free quantity divided by the requested quantity over all requested types.
+// Types not defined in the capacity are considered unlimited, types not defined in used are considered 0.
+// A free quantity that is negative is treated as 0. Requested types with a zero or negative quantity are skipped.
+// If the capacity is unlimited for all requested types, or the request is nil, math.MaxInt64 is returned.
+func FitInCount(capacity, used, request *Resource) int64 {
+	count := Quantity(math.MaxInt64)
+	if request == nil || capacity == nil {
+		return int64(count)
+	}
+	for k, v := range request.Resources {
+		if v <= 0 {
+			continue
+		}
+		capVal, ok := capacity.Resources[k]
+		if !ok {
+			continue
+		}
+		var usedVal Quantity
+		if used != nil {
+			usedVal = used.Resources[k]
+		}
+		count = min(count, max(0, subVal(capVal, usedVal))/v)
+	}
+	return int64(count)
+}
+
+// SplitByWeights splits the total resource into shares proportional to the weights: each share is
+// total * (weight / sum of weights) for each type, rounded down. The units left after rounding down are distributed
+// one at a time using the largest remainder method so that the shares of a type sum up to the total. Remainders that
+// are the same are handed out in sorted order of the share names.
+// Shares with a zero or negative weight are returned as an empty resource and do not count towards the sum.
+// A nil total is treated as an empty resource. The calculation uses floating point values, for very large quantities
+// rounding might cause the shares to not sum up exactly to the total.
+func SplitByWeights(total *Resource, weights map[string]float64) map[string]*Resource {
+	shares := make(map[string]*Resource, len(weights))
+	var names []string
+	var sum float64
+	for name, weight := range weights {
+		shares[name] = NewResource()
+		if weight > 0 {
+			names = append(names, name)
+			sum += weight
 		}
-		largerValue = max(0, largerValue)
-		if v > largerValue {
-			return false
+	}
+	if total == nil || len(names) == 0 {
+		return shares
+	}
+	sort.Strings(names)
+	remainders := make(map[string]float64, len(names))
+	for k, v := range total.Resources {
+		assigned := Quantity(0)
+		for _, name := range names {
+			ratio := weights[name] / sum
+			part := mulValRatio(v, ratio)
+			exact := float64(v) * ratio
+			// multiplying rounds towards zero, round down for negative values
+			if float64(part) > exact {
+				part--
+			}
+			remainders[name] = exact - float64(part)
+			shares[name].Resources[k] = part
+			assigned = addVal(assigned, part)
+		}
+		// hand out the left over units to the largest remainders, stable sort keeps the names in order for ties
+		byRemainder := append([]string(nil), names...)
+		sort.SliceStable(byRemainder, func(i, j int) bool {
+			return remainders[byRemainder[i]] > remainders[byRemainder[j]]
+		})
+		leftOver := max(0, min(subVal(v, assigned), Quantity(len(names))))
+		for i := Quantity(0); i < leftOver; i++ {
+			shares[byRemainder[i]].Resources[k]++
 		}
 	}
-	return true
+	return shares
 }
 
 // getShareFairForDenominator attempts to computes the denominator for a queue's fair share ratio.
@@ -539,6 +1704,13 @@ func getFairShare(allocated, guaranteed, fair *Resource) float64 {
 	return maxShare
 }
 
+// FairShare returns the ratio which represents the current 'fair' share usage of the allocated resource.
+// The calculation is the same as used by CompUsageRatioSeparately: guaranteed resources are used as the denominator
+// and the fair max resources are used as a fallback for types not guaranteed.
+func FairShare(allocated, guaranteed, fair *Resource) float64 {
+	return getFairShare(allocated, guaranteed, fair)
+}
+
 // Get the share of each resource quantity when compared to the total
 // resources quantity
 // NOTE: shares can be negative and positive in the current assumptions
@@ -586,6 +1758,46 @@ func getShares(res, total *Resource) []float64 {
 	return shares
 }
 
+// SharesOf returns the share of each resource quantity when compared to the total resources quantity.
+// The shares are sorted in increasing order and can be compared using CompareShareVectors.
+// NOTE: shares can be negative and positive in the current assumptions
+func SharesOf(res, total *Resource) []float64 {
+	return getShares(res, total)
+}
+
+// RatioVector returns the share of each resource type defined in the resource it is called on when compared to
+// the total. The shares are calculated in the same way as getShares does but are keyed by the resource type.
+// The share is the quantity itself if total is nil or zero for the type.
+// A nil resource returns an empty map.
+func (r *Resource) RatioVector(total *Resource) map[string]float64 {
+	ratios := make(map[string]float64)
+	if r == nil {
+		return ratios
+	}
+	for k, v := range r.Resources {
+		switch {
+		case v == 0:
+			ratios[k] = 0
+		case total == nil || total.Resources[k] == 0:
+			ratios[k] = float64(v)
+		default:
+			ratios[k] = float64(v) / float64(total.Resources[k])
+		}
+	}
+	return ratios
+}
+
+// OvercommitRatio returns the ratio of committed compared to guaranteed for each type defined in committed.
+// A value larger than 1 means the type is committed past the guaranteed quantity. The ratio is calculated in the
+// same way as RatioVector: if guaranteed is zero, or not defined, for the type the committed quantity is returned.
+// A nil committed or guaranteed resource returns an empty map.
+func OvercommitRatio(committed, guaranteed *Resource) map[string]float64 {
+	if guaranteed == nil {
+		return make(map[string]float64)
+	}
+	return committed.RatioVector(guaranteed)
+}
+
 // Calculate share for left of total and right of total.
 // This returns the same value as compareShares does:
 // 0 for equal shares
@@ -598,6 +1810,19 @@ func CompUsageRatio(left, right, total *Resource) int {
 	return compareShares(lshares, rshares)
 }
 
+// CompareByCapacityShare compares the dominant share of the capacity of left and right. The dominant share is the
+// usage ratio of the type returned by DominantResourceType, a resource without a dominant type has a share of 0.
+// Resources with the same dominant share are compared on their L1Norm. This returns:
+// 0 for equal shares and norms
+// 1 if the left share is larger
+// -1 if the right share is larger
+func CompareByCapacityShare(left, right, capacity *Resource) int {
+	if result := cmp.Compare(left.dominantShare(capacity), right.dominantShare(capacity)); result != 0 {
+		return result
+	}
+	return cmp.Compare(left.L1Norm(), right.L1Norm())
+}
+
 // Calculate share for left of total and right of total separately.
 // This returns the same value as compareShares does:
 // 0 for equal shares
@@ -693,6 +1918,14 @@ func compareShares(lshares, rshares []float64) int {
 	return 0
 }
 
+// CompareShareVectors compares the shares as returned by SharesOf and returns the compared value
+// 0 for equal shares
+// 1 if the left share is larger
+// -1 if the right share is larger
+func CompareShareVectors(left, right []float64) int {
+	return compareShares(left, right)
+}
+
 // Equals Compare the resources based on common resource type available in both left and right Resource
 // Resource type available in left Resource but not in right Resource and vice versa is not taken into account
 // False in case anyone of the resources is nil
@@ -720,6 +1953,84 @@ func Equals(left, right *Resource) bool {
 	return true
 }
 
+// EqualsIgnoring compares the resources in the same way as Equals does after excluding the types listed in ignore from
+// both resources. The values of the ignored types do not influence the result.
+// False in case anyone of the resources is nil
+func EqualsIgnoring(left, right *Resource, ignore []string) bool {
+	if left == right {
+		return true
+	}
+	if left == nil || right == nil {
+		return false
+	}
+	skip := make(map[string]bool, len(ignore))
+	for _, k := range ignore {
+		skip[k] = true
+	}
+	for k, v := range left.Resources {
+		if !skip[k] && right.Resources[k] != v {
+			return false
+		}
+	}
+	for k, v := range right.Resources {
+		if !skip[k] && left.Resources[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualsWithinTolerance compares the resources in the same way as Equals does but allows the values to differ by
+// at most the tolerance. A type not defined in one of the resources is considered 0 in that resource.
+// A negative tolerance is treated as 0
+// False in case anyone of the resources is nil
+func EqualsWithinTolerance(left, right *Resource, tolerance Quantity) bool {
+	if left == right {
+		return true
+	}
+	if left == nil || right == nil {
+		return false
+	}
+	tolerance = max(0, tolerance)
+	for k, v := range left.Resources {
+		if absDiff(v, right.Resources[k]) > uint64(tolerance) {
+			return false
+		}
+	}
+	for k, v := range right.Resources {
+		if absDiff(v, left.Resources[k]) > uint64(tolerance) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualsIgnoringZeros compares the resources only taking the types with a value that is not zero into account.
+// A type with a zero value is considered equal to a type that is not defined: {cpu:1, mem:0} equals {cpu:1}.
+// A nil or empty resource is equal to a resource with only zero values.
+func EqualsIgnoringZeros(left, right *Resource) bool {
+	if left == right {
+		return true
+	}
+	if left == nil {
+		left = Zero // shadows in the local function not seen by the callers.
+	}
+	if right == nil {
+		right = Zero // shadows in the local function not seen by the callers.
+	}
+	for k, v := range left.Resources {
+		if v != right.Resources[k] {
+			return false
+		}
+	}
+	for k, v := range right.Resources {
+		if v != left.Resources[k] {
+			return false
+		}
+	}
+	return true
+}
+
 // DeepEquals Compare the resources based on resource type existence and its values as well
 // False in case anyone of the resources is nil
 // False in case resource length differs
@@ -748,6 +2059,58 @@ func DeepEquals(left, right *Resource) bool {
 	return true
 }
 
+// Compare the resources providing a total ordering, returns the specific values for following cases:
+// 0 if the resources are equal: same types and the same value for each type
+// -1 if left sorts before right
+// 1 if left sorts after right
+// The sorted types of the resources are compared first: lexicographically by name, with a resource whose types are a
+// prefix of the types of the other resource sorting first. Only resources with the same types are compared on their
+// values, in the order of the sorted types.
+// A nil resource sorts before any non-nil resource, including an empty resource.
+func Compare(left, right *Resource) int {
+	switch {
+	case left == right:
+		return 0
+	case left == nil:
+		return -1
+	case right == nil:
+		return 1
+	}
+	keys := sortedKeys(left)
+	if c := slices.Compare(keys, sortedKeys(right)); c != 0 {
+		return c
+	}
+	for _, k := range keys {
+		if c := cmp.Compare(left.Resources[k], right.Resources[k]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// RelationPerType compares the resources per type and returns the relation for each type in the union of both
+// resources: 1 if left is larger, 0 if equal and -1 if right is larger than left.
+// A type not defined in one of the resources is considered 0 in that resource.
+// A nil resource is treated as an empty resource.
+func RelationPerType(left, right *Resource) map[string]int {
+	relations := make(map[string]int)
+	if left == nil {
+		left = Zero // shadows in the local function not seen by the callers.
+	}
+	if right == nil {
+		right = Zero // shadows in the local function not seen by the callers.
+	}
+	for k, v := range left.Resources {
+		relations[k] = cmp.Compare(v, right.Resources[k])
+	}
+	for k, v := range right.Resources {
+		if _, ok := left.Resources[k]; !ok {
+			relations[k] = cmp.Compare(0, v)
+		}
+	}
+	return relations
+}
+
 // MatchAny returns true if at least one type in the defined resource exists in the other resource.
 // False if none of the types exist in the other resource.
 // A nil resource is treated as an empty resource (no types defined) and returns false
@@ -767,6 +2130,122 @@ func (r *Resource) MatchAny(other *Resource) bool {
 	return false
 }
 
+// IsSubsetOf returns true if all types in the defined resource exist in the other resource.
+// A nil or empty resource is a subset of any resource including a nil resource.
+// A resource with types defined is not a subset of a nil resource.
+// Values are not considered during the checks
+func (r *Resource) IsSubsetOf(other *Resource) bool {
+	if r.IsEmpty() {
+		return true
+	}
+	if other == nil {
+		return false
+	}
+	for k := range r.Resources {
+		if _, ok := other.Resources[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// TypeUnion returns the sorted list of types defined in at least one of the resources.
+// A nil resource is treated as an empty resource (no types defined)
+// Values are not considered during the checks
+func TypeUnion(left, right *Resource) []string {
+	union := sortedKeys(left)
+	for _, k := range sortedKeys(right) {
+		if left == nil {
+			union = append(union, k)
+			continue
+		}
+		if _, ok := left.Resources[k]; !ok {
+			union = append(union, k)
+		}
+	}
+	sort.Strings(union)
+	return union
+}
+
+// TypeIntersection returns the sorted list of types defined in both resources.
+// A nil resource is treated as an empty resource (no types defined)
+// Values are not considered during the checks
+func TypeIntersection(left, right *Resource) []string {
+	intersection := make([]string, 0)
+	if right == nil {
+		return intersection
+	}
+	for _, k := range sortedKeys(left) {
+		if _, ok := right.Resources[k]; ok {
+			intersection = append(intersection, k)
+		}
+	}
+	return intersection
+}
+
+// ValidateTypes checks that all types in the defined resource are allowed. The returned error lists all the types,
+// in sorted order, that are not allowed.
+// A nil resource is always valid. An empty or nil allowed set rejects all types.
+// Values are not considered during the checks
+func (r *Resource) ValidateTypes(allowed map[string]bool) error {
+	var invalid []string
+	for _, k := range sortedKeys(r) {
+		if !allowed[k] {
+			invalid = append(invalid, k)
+		}
+	}
+	if len(invalid) != 0 {
+		return errors.New("resource type not allowed: " + strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// ResourceBounds defines the minimum and maximum quantity, both inclusive, allowed per resource type.
+type ResourceBounds map[string]struct{ Min, Max Quantity }
+
+// ValidateBounds checks that each quantity of the resource is within the bounds defined for the type. The error
+// returned lists all types, in sorted order, with a quantity that is too low or too high.
+// Types without bounds are always valid, bounds for types not defined in the resource are ignored.
+// A nil resource is always valid.
+func (r *Resource) ValidateBounds(bounds ResourceBounds) error {
+	var invalid []string
+	for _, k := range sortedKeys(r) {
+		bound, ok := bounds[k]
+		if !ok {
+			continue
+		}
+		v := r.Resources[k]
+		switch {
+		case v < bound.Min:
+			invalid = append(invalid, fmt.Sprintf("%s (%d below minimum %d)", k, v, bound.Min))
+		case v > bound.Max:
+			invalid = append(invalid, fmt.Sprintf("%s (%d above maximum %d)", k, v, bound.Max))
+		}
+	}
+	if len(invalid) != 0 {
+		return errors.New("resource out of bounds: " + strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// IsFullyDefined checks that all required resource types are defined in the resource. Returns true if all
+// types are defined and the list of required types that are not defined, in the order of the required list.
+// The list is empty if all types are defined. A nil resource returns false with all required types missing.
+// Values are not considered during the checks, a type with a zero value is defined.
+func (r *Resource) IsFullyDefined(required []string) (bool, []string) {
+	missing := make([]string, 0)
+	for _, k := range required {
+		if r == nil {
+			missing = append(missing, k)
+			continue
+		}
+		if _, ok := r.Resources[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	return r != nil && len(missing) == 0, missing
+}
+
 // Compare the resources equal returns the specific values for following cases:
 // left  right  return
 // nil   nil    true
@@ -791,26 +2270,253 @@ func Multiply(base *Resource, ratio int64) *Resource {
 	if base == nil || ratio == 0 {
 		return ret
 	}
-	qRatio := Quantity(ratio)
-	for k, v := range base.Resources {
-		ret.Resources[k] = mulVal(v, qRatio)
+	qRatio := Quantity(ratio)
+	for k, v := range base.Resources {
+		ret.Resources[k] = mulVal(v, qRatio)
+	}
+	return ret
+}
+
+// MultiplyChecked multiplies the resource by the integer ratio returning a new resource, the same as Multiply.
+// An error is returned, listing the types in sorted order, if the multiplication wrapped for any of the types. The
+// result is always returned and has the wrapped quantities clamped to the maximum or minimum value possible.
+// A nil resource passed in returns a new empty resource (zero)
+func MultiplyChecked(base *Resource, ratio int64) (*Resource, error) {
+	var wrapped []string
+	for _, k := range sortedKeys(base) {
+		if mulWraps(base.Resources[k], Quantity(ratio)) {
+			wrapped = append(wrapped, k)
+		}
+	}
+	ret := Multiply(base, ratio)
+	if len(wrapped) != 0 {
+		return ret, errors.New("multiplication overflow for resource types: " + strings.Join(wrapped, ", "))
+	}
+	return ret, nil
+}
+
+// Multiply the resource by the floating point ratio returning a new resource.
+// The result is rounded down to the nearest integer value after the multiplication.
+// Result is protected from overflow (positive and negative).
+// A nil resource passed in returns a new empty resource (zero)
+func MultiplyBy(base *Resource, ratio float64) *Resource {
+	ret := NewResource()
+	if base == nil || ratio == 0 {
+		return ret
+	}
+	for k, v := range base.Resources {
+		ret.Resources[k] = mulValRatio(v, ratio)
+	}
+	return ret
+}
+
+// Multiply the resource by the floating point ratio returning a new resource.
+// The result is rounded up to the nearest integer value after the multiplication, contrary to MultiplyBy which
+// rounds down. Rounding up is towards positive infinity also for negative values.
+// Result is protected from overflow (positive and negative).
+// A nil resource passed in returns a new empty resource (zero)
+func MultiplyByCeil(base *Resource, ratio float64) *Resource {
+	ret := NewResource()
+	if base == nil || ratio == 0 {
+		return ret
+	}
+	for k, v := range base.Resources {
+		ret.Resources[k] = mulValRatioCeil(v, ratio)
+	}
+	return ret
+}
+
+// ScaleDimension returns a new resource with only the quantity of the given type multiplied by the floating point
+// ratio. The result is rounded down to the nearest integer value after the multiplication, the same as MultiplyBy.
+// All other types are unchanged. If the type is not defined in the resource an unchanged copy is returned.
+// Result is protected from overflow (positive and negative).
+// A nil resource passed in returns nil
+func (r *Resource) ScaleDimension(key string, ratio float64) *Resource {
+	if r == nil {
+		return nil
+	}
+	out := r.Clone()
+	if v, ok := out.Resources[key]; ok {
+		out.Resources[key] = mulValRatio(v, ratio)
+	}
+	return out
+}
+
+// QuantizeUp returns a new resource with each quantity rounded up to the next multiple of the step defined for
+// that type. Rounding up is towards positive infinity also for negative values.
+// Types without a step are unchanged, a step of zero or less for a type leaves that type unchanged.
+// Result is protected from overflow.
+// A nil resource passed in returns nil, a nil step returns a copy of the resource.
+func (r *Resource) QuantizeUp(step *Resource) *Resource {
+	return r.quantize(step, func(value, stepVal, remainder Quantity) Quantity {
+		return addVal(value, stepVal-remainder)
+	})
+}
+
+// QuantizeDown returns a new resource with each quantity rounded down to the previous multiple of the step defined for
+// that type. Rounding down is towards negative infinity also for negative values.
+// Types without a step are unchanged, a step of zero or less for a type leaves that type unchanged.
+// Result is protected from overflow.
+// A nil resource passed in returns nil, a nil step returns a copy of the resource.
+func (r *Resource) QuantizeDown(step *Resource) *Resource {
+	return r.quantize(step, func(value, _, remainder Quantity) Quantity {
+		return subVal(value, remainder)
+	})
+}
+
+// quantize returns a new resource with each quantity that is not a multiple of the step defined for that type
+// replaced by the result of the round function. The round function is called with the quantity, the step and the
+// remainder: the distance from the previous multiple of the step towards negative infinity, always in (0, step).
+// Types without a step, or with a step of zero or less, are unchanged.
+// A nil resource passed in returns nil, a nil step returns a copy of the resource.
+func (r *Resource) quantize(step *Resource, round func(value, stepVal, remainder Quantity) Quantity) *Resource {
+	if r == nil {
+		return nil
+	}
+	out := r.Clone()
+	if step == nil {
+		return out
+	}
+	for k, v := range r.Resources {
+		stepVal, ok := step.Resources[k]
+		if !ok {
+			continue
+		}
+		if stepVal <= 0 {
+			log.Log(log.Resources).Debug("ignoring invalid step for quantize",
+				zap.String("resource", k),
+				zap.Int64("step", int64(stepVal)))
+			continue
+		}
+		remainder := v % stepVal
+		if remainder < 0 {
+			remainder += stepVal
+		}
+		if remainder != 0 {
+			out.Resources[k] = round(v, stepVal, remainder)
+		}
+	}
+	return out
+}
+
+// QuantizeNearest returns a new resource with each quantity rounded to the nearest multiple of the step defined for
+// that type. A quantity exactly halfway between two multiples is rounded up, towards positive infinity also for
+// negative values.
+// Types without a step are unchanged, a step of zero or less for a type leaves that type unchanged.
+// Result is protected from overflow.
+// A nil resource passed in returns nil, a nil step returns a copy of the resource.
+func (r *Resource) QuantizeNearest(step *Resource) *Resource {
+	return r.quantize(step, func(value, stepVal, remainder Quantity) Quantity {
+		// compare against the distance to the next multiple, doubling the remainder could overflow
+		if remainder >= stepVal-remainder {
+			return addVal(value, stepVal-remainder)
+		}
+		return subVal(value, remainder)
+	})
+}
+
+// Lerp returns a new resource linearly interpolated between the start and end resource: start + (end-start)*t.
+// The interpolation factor t is clamped to the range [0,1]. The result contains the union of the types defined in
+// start and end, a type not defined in one of the resources is considered zero in that resource.
+// The result is rounded down to the nearest integer value and protected from overflow (positive and negative).
+// Nil resources are considered empty resources.
+func Lerp(start, end *Resource, t float64) *Resource {
+	t = max(0, min(t, 1))
+	var from, to map[string]Quantity
+	if start != nil {
+		from = start.Resources
+	}
+	if end != nil {
+		to = end.Resources
+	}
+	out := NewResource()
+	for k, v := range from {
+		out.Resources[k] = lerpVal(v, to[k], t)
+	}
+	for k, v := range to {
+		if _, ok := from[k]; !ok {
+			out.Resources[k] = lerpVal(0, v, t)
+		}
+	}
+	return out
+}
+
+// Blend returns a new resource with the weighted blend of the old and new resource: old*(1-alpha) + new*alpha.
+// This is the same as Lerp from the old to the new resource with alpha as the interpolation factor, see Lerp for the
+// clamping, rounding and overflow behaviour.
+// Nil resources are considered empty resources.
+func Blend(oldRes, newRes *Resource, alpha float64) *Resource {
+	return Lerp(oldRes, newRes, alpha)
+}
+
+// lerpVal interpolates between the two quantities: from + (to-from)*t, with t in the range [0,1].
+// The result is rounded down (towards negative infinity) and always lies between from and to.
+// The difference is used as long as it does not wrap, only spans larger than the maximum quantity use a float
+// calculation which is clamped to the minimum or maximum value possible.
+func lerpVal(from, to Quantity, t float64) Quantity {
+	// the edges are exact: a float cannot represent all quantities
+	switch t {
+	case 0:
+		return from
+	case 1:
+		return to
+	}
+	diff := to - from
+	if (to >= from) == (diff >= 0) {
+		// the difference did not wrap: move from start towards end, the result is always between both values
+		part := math.Floor(float64(diff) * t)
+		if (diff >= 0 && part >= float64(diff)) || (diff < 0 && part <= float64(diff)) {
+			return to
+		}
+		return from + Quantity(part)
+	}
+	result := math.Floor(float64(from)*(1-t) + float64(to)*t)
+	// MaxInt64 as a float is rounded up to 2^63
+	if result >= math.MaxInt64 {
+		log.Log(log.Resources).Warn("Interpolation result positive overflow",
+			zap.Int64("from", int64(from)),
+			zap.Int64("to", int64(to)),
+			zap.Float64("factor", t))
+		notifyOverflow("lerp", from, to, t)
+		return math.MaxInt64
+	}
+	if result < math.MinInt64 {
+		log.Log(log.Resources).Warn("Interpolation result negative overflow",
+			zap.Int64("from", int64(from)),
+			zap.Int64("to", int64(to)),
+			zap.Float64("factor", t))
+		notifyOverflow("lerp", from, to, t)
+		return math.MinInt64
 	}
-	return ret
+	return Quantity(result)
 }
 
-// Multiply the resource by the floating point ratio returning a new resource.
-// The result is rounded down to the nearest integer value after the multiplication.
-// Result is protected from overflow (positive and negative).
-// A nil resource passed in returns a new empty resource (zero)
-func MultiplyBy(base *Resource, ratio float64) *Resource {
-	ret := NewResource()
-	if base == nil || ratio == 0 {
-		return ret
-	}
-	for k, v := range base.Resources {
-		ret.Resources[k] = mulValRatio(v, ratio)
+// GrowthRate returns the change per second for each type between the earlier and later resource:
+// (later - earlier) / seconds. The result contains the union of the types defined in earlier and later, a type not
+// defined in one of the resources is considered zero in that resource. A shrinking type has a negative rate.
+// The difference is calculated using floats and cannot overflow.
+// Nil resources are considered empty resources. Zero or negative seconds return an empty map.
+func GrowthRate(earlier, later *Resource, seconds float64) map[string]float64 {
+	rates := make(map[string]float64)
+	if seconds <= 0 {
+		return rates
+	}
+	var from, to map[string]Quantity
+	if earlier != nil {
+		from = earlier.Resources
+	}
+	if later != nil {
+		to = later.Resources
+	}
+	for k, v := range from {
+		rates[k] = (float64(to[k]) - float64(v)) / seconds
+	}
+	for k, v := range to {
+		if _, ok := from[k]; !ok {
+			rates[k] = float64(v) / seconds
+		}
 	}
-	return ret
+	return rates
 }
 
 // Return true if all quantities in larger > smaller
@@ -873,6 +2579,19 @@ func StrictlyGreaterThanOrEquals(larger, smaller *Resource) bool {
 	return true
 }
 
+// Return true if all quantities in smaller < larger
+// This is the mirror of StrictlyGreaterThan: two resources that are equal are not considered strictly smaller than
+// each other.
+func StrictlyLessThan(smaller, larger *Resource) bool {
+	return StrictlyGreaterThan(larger, smaller)
+}
+
+// Return true if all quantities in smaller < larger or if the two objects are exactly the same.
+// This is the mirror of StrictlyGreaterThanOrEquals.
+func StrictlyLessThanOrEquals(smaller, larger *Resource) bool {
+	return StrictlyGreaterThanOrEquals(larger, smaller)
+}
+
 // StrictlyGreaterThanOnlyExisting returns true if all quantities for types in the defined resource are greater than
 // the quantity for the same type in smaller.
 // Types defined in smaller that are not in the defined resource are ignored.
@@ -971,6 +2690,35 @@ func ComponentWiseMin(left, right *Resource) *Resource {
 	return out
 }
 
+// ComponentWiseMinWeighted returns a new Resource with the smallest value for each quantity in the Resources, same as
+// ComponentWiseMin, except for the types marked in prefer which use the largest value instead.
+// If either Resource passed in is nil the other Resource is returned
+// If a Resource type is missing from one of the Resource, it is considered empty and the quantity from the other Resource is returned
+func ComponentWiseMinWeighted(left, right *Resource, prefer map[string]bool) *Resource {
+	if right == nil && left == nil {
+		return nil
+	}
+	if left == nil {
+		return right.Clone()
+	}
+	if right == nil {
+		return left.Clone()
+	}
+	out := right.Clone()
+	for k, v := range left.Resources {
+		val, ok := right.Resources[k]
+		switch {
+		case !ok:
+			out.Resources[k] = v
+		case prefer[k]:
+			out.Resources[k] = max(v, val)
+		default:
+			out.Resources[k] = min(v, val)
+		}
+	}
+	return out
+}
+
 // MergeIfNotPresent Returns a new Resource by merging resource type values present in right with left
 // only if resource type not present in left.
 // If either Resource passed in is nil the other Resource is returned
@@ -994,6 +2742,52 @@ func MergeIfNotPresent(left, right *Resource) *Resource {
 	return out
 }
 
+// MergeMax Returns a new Resource by merging resource type values present in right with left. If the resource type
+// is present in both the largest value is used.
+// If either Resource passed in is nil the other Resource is returned (contrary to ComponentWiseMax)
+// If a Resource type is missing from one of the Resource, the quantity from the other Resource is returned
+func MergeMax(left, right *Resource) *Resource {
+	if right == nil && left == nil {
+		return nil
+	}
+	if left == nil {
+		return right.Clone()
+	}
+	if right == nil {
+		return left.Clone()
+	}
+	out := left.Clone()
+	for k, v := range right.Resources {
+		if val, ok := left.Resources[k]; ok {
+			out.Resources[k] = max(v, val)
+		} else {
+			out.Resources[k] = v
+		}
+	}
+	return out
+}
+
+// Override Returns a new Resource with the resource type values present in overrides replacing the values in base,
+// including zero values. Resource types only present in base are retained.
+// This is the reverse of MergeIfNotPresent which only adds the types that are not present in base.
+// If either Resource passed in is nil the other Resource is returned
+func Override(base, overrides *Resource) *Resource {
+	if overrides == nil && base == nil {
+		return nil
+	}
+	if base == nil {
+		return overrides.Clone()
+	}
+	out := base.Clone()
+	if overrides == nil {
+		return out
+	}
+	for k, v := range overrides.Resources {
+		out.Resources[k] = v
+	}
+	return out
+}
+
 // ComponentWiseMinOnlyExisting Returns a new Resource with the smallest value for resource type
 // existing only in left but not vice versa.
 func ComponentWiseMinOnlyExisting(left, right *Resource) *Resource {
@@ -1017,6 +2811,14 @@ func ComponentWiseMinOnlyExisting(left, right *Resource) *Resource {
 	return out
 }
 
+// CapTo returns a new Resource with each quantity of the resource limited to the quantity defined in the ceiling.
+// Types that are not defined in the ceiling are unchanged, types only defined in the ceiling are ignored.
+// This is the method form of ComponentWiseMinOnlyExisting with the ceiling as the right resource.
+// A nil ceiling returns a clone of the resource, a nil resource returns nil
+func (r *Resource) CapTo(ceiling *Resource) *Resource {
+	return ComponentWiseMinOnlyExisting(r, ceiling)
+}
+
 func (r *Resource) HasNegativeValue() bool {
 	if r == nil {
 		return false
@@ -1029,11 +2831,102 @@ func (r *Resource) HasNegativeValue() bool {
 	return false
 }
 
+// AllPositive returns true if every type defined in the resource has a quantity larger than zero.
+// A nil or empty resource returns false: there is no value that is positive.
+// Contrary to AllNonNegative which returns true for a nil or empty resource.
+func (r *Resource) AllPositive() bool {
+	if r == nil || len(r.Resources) == 0 {
+		return false
+	}
+	for _, v := range r.Resources {
+		if v <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AllNonNegative returns true if no type defined in the resource has a quantity smaller than zero.
+// A nil or empty resource returns true: there is no value that is negative.
+// Contrary to AllPositive which returns false for a nil or empty resource.
+func (r *Resource) AllNonNegative() bool {
+	return !r.HasNegativeValue()
+}
+
+// NonZeroCount returns the number of types in the resource with a quantity that is not zero.
+// A nil resource returns 0
+func (r *Resource) NonZeroCount() int {
+	if r == nil {
+		return 0
+	}
+	count := 0
+	for _, v := range r.Resources {
+		if v != 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// PositiveCount returns the number of types in the resource with a quantity larger than zero.
+// A nil resource returns 0
+func (r *Resource) PositiveCount() int {
+	if r == nil {
+		return 0
+	}
+	count := 0
+	for _, v := range r.Resources {
+		if v > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Signs returns the sign of each quantity in the resource: -1 for a negative, 0 for a zero and 1 for a positive value.
+// A nil resource returns an empty map
+func (r *Resource) Signs() map[string]int {
+	signs := make(map[string]int)
+	if r == nil {
+		return signs
+	}
+	for k, v := range r.Resources {
+		switch {
+		case v < 0:
+			signs[k] = -1
+		case v > 0:
+			signs[k] = 1
+		default:
+			signs[k] = 0
+		}
+	}
+	return signs
+}
+
 // IsEmpty returns true if the resource is nil or has no component resources specified.
 func (r *Resource) IsEmpty() bool {
 	return r == nil || len(r.Resources) == 0
 }
 
+// IsNil returns true if the resource is nil. Contrary to IsEmpty a resource without any types defined is not nil.
+func IsNil(r *Resource) bool {
+	return r == nil
+}
+
+// HasExplicitZeros returns true if at least one type in the resource is defined with a quantity of exactly zero.
+// A nil or empty resource has no types defined and returns false.
+func (r *Resource) HasExplicitZeros() bool {
+	if r == nil {
+		return false
+	}
+	for _, v := range r.Resources {
+		if v == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Returns a new resource with the largest value for each quantity in the resources
 // If either resource passed in is nil a zero resource is returned
 func ComponentWiseMax(left, right *Resource) *Resource {
@@ -1049,6 +2942,126 @@ func ComponentWiseMax(left, right *Resource) *Resource {
 	return out
 }
 
+// ComponentWiseAverage returns a new Resource with the integer mean of each quantity over the resources in the list.
+// The mean for a type is calculated over the resources that define the type, not over all resources in the list:
+// the average of [{cpu:2, mem:4}, {cpu:4}] is {cpu:3, mem:4}. The mean is rounded towards zero.
+// Nil resources in the list are skipped. Summing the quantities is protected from overflow.
+// An empty list or a list with only nil resources returns an empty resource.
+func ComponentWiseAverage(resources []*Resource) *Resource {
+	out := NewResource()
+	counts := make(map[string]Quantity)
+	for _, res := range resources {
+		if res == nil {
+			continue
+		}
+		for k, v := range res.Resources {
+			out.Resources[k] = addVal(out.Resources[k], v)
+			counts[k]++
+		}
+	}
+	for k, count := range counts {
+		out.Resources[k] /= count
+	}
+	return out
+}
+
+// BoundingBox returns a new Resource with the largest value for each quantity over the requests in the list, and the
+// number of requests used. The largest value for a type is taken over the requests that define the type: a request
+// that fits in the returned resource fits in it regardless of which request of the list it is.
+// Nil requests in the list are skipped and not counted.
+// An empty list or a list with only nil requests returns an empty resource and a count of 0.
+func BoundingBox(requests []*Resource) (*Resource, int) {
+	out := NewResource()
+	count := 0
+	for _, req := range requests {
+		if req == nil {
+			continue
+		}
+		count++
+		for k, v := range req.Resources {
+			if val, ok := out.Resources[k]; !ok || v > val {
+				out.Resources[k] = v
+			}
+		}
+	}
+	return out, count
+}
+
+// Clamp returns a new Resource with each quantity of the resource bounded by the lower and upper bound.
+// A type not defined in the lower or upper bound is not bounded on that side, a nil bound means no bound at all.
+// Types defined in the bounds that are not defined in the resource are ignored.
+// If the lower bound is larger than the upper bound for a type the upper bound is returned.
+// A nil resource passed in returns nil
+func Clamp(r, lower, upper *Resource) *Resource {
+	if r == nil {
+		return nil
+	}
+	out := NewResource()
+	for k, v := range r.Resources {
+		if lower != nil {
+			if val, ok := lower.Resources[k]; ok {
+				v = max(v, val)
+			}
+		}
+		if upper != nil {
+			if val, ok := upper.Resources[k]; ok {
+				v = min(v, val)
+			}
+		}
+		out.Resources[k] = v
+	}
+	return out
+}
+
+// ExceedsInt32 returns the types in the resource, in sorted order, with an absolute value larger than math.MaxInt32.
+// A nil or empty resource returns an empty list.
+func (r *Resource) ExceedsInt32() []string {
+	exceeds := make([]string, 0)
+	for _, k := range sortedKeys(r) {
+		if absDiff(r.Resources[k], 0) > math.MaxInt32 {
+			exceeds = append(exceeds, k)
+		}
+	}
+	return exceeds
+}
+
+// ValidateMax returns the types in the resource, in sorted order, with a value larger than the limit.
+// A nil or empty resource returns an empty list.
+func (r *Resource) ValidateMax(limit Quantity) []string {
+	return r.typesWhere(func(v Quantity) bool { return v > limit })
+}
+
+// ValidateMin returns the types in the resource, in sorted order, with a value smaller than the limit.
+// A nil or empty resource returns an empty list.
+func (r *Resource) ValidateMin(limit Quantity) []string {
+	return r.typesWhere(func(v Quantity) bool { return v < limit })
+}
+
+// typesWhere returns the types in the resource, in sorted order, for which the check returns true.
+func (r *Resource) typesWhere(check func(v Quantity) bool) []string {
+	types := make([]string, 0)
+	for _, k := range sortedKeys(r) {
+		if check(r.Resources[k]) {
+			types = append(types, k)
+		}
+	}
+	return types
+}
+
+// ClampToInt32 returns a new Resource with each quantity that has an absolute value larger than math.MaxInt32 capped
+// to an absolute value of math.MaxInt32, keeping the sign of the quantity. The types found by ExceedsInt32 are changed.
+// A nil resource passed in returns nil
+func (r *Resource) ClampToInt32() *Resource {
+	if r == nil {
+		return nil
+	}
+	out := NewResource()
+	for k, v := range r.Resources {
+		out.Resources[k] = max(-math.MaxInt32, min(v, math.MaxInt32))
+	}
+	return out
+}
+
 // Check that the whole resource is zero
 // A nil or empty resource is zero (contrary to StrictlyGreaterThanZero)
 func IsZero(zero *Resource) bool {
@@ -1071,13 +3084,31 @@ func IsZero(zero *Resource) bool {
 // math.MaxInt32 (resolved value 2147483647)
 func CalculateAbsUsedCapacity(capacity, used *Resource) *Resource {
 	absResource := NewResource()
+	for resourceName, absUsed := range CalculateAbsUsedCapacityFloat(capacity, used) {
+		// we really do not want to show a percentage value that is larger than a 32-bit integer.
+		// even that is already really large and could easily lead to UI render issues.
+		if absUsed > float64(math.MaxInt32) {
+			absResource.Resources[resourceName] = math.MaxInt32
+		} else {
+			absResource.Resources[resourceName] = Quantity(absUsed)
+		}
+	}
+	return absResource
+}
+
+// CalculateAbsUsedCapacityFloat returns absolute used as a percentage, without truncation, for each defined resource
+// named in the capacity comparing usage to the capacity.
+// If usage is 0 or below 0, absolute used is always 0
+// if capacity is 0 or below 0, absolute used is always 100
+// if used is larger than capacity a value larger than 100 can be returned. The percentage value is not capped.
+func CalculateAbsUsedCapacityFloat(capacity, used *Resource) map[string]float64 {
+	absUsed := make(map[string]float64)
 	if capacity == nil || used == nil {
 		log.Log(log.Resources).Debug("Cannot calculate absolute capacity because of missing capacity or usage")
-		return absResource
+		return absUsed
 	}
 	missingResources := &strings.Builder{}
 	for resourceName, capResource := range capacity.Resources {
-		var absResValue int64
 		usedResource, ok := used.Resources[resourceName]
 		// track this for troubleshooting only
 		if !ok {
@@ -1091,29 +3122,90 @@ func CalculateAbsUsedCapacity(capacity, used *Resource) *Resource {
 		// used is 0 or below nothing is used -> 0%
 		// below 0 should never happen
 		case usedResource <= 0:
-			absResValue = 0
+			absUsed[resourceName] = 0
 		// capacity is 0 or below any usage is full -> 100% (prevents divide by 0)
 		// below 0 should never happen
 		case capResource <= 0:
-			absResValue = 100
-		// calculate percentage: never wraps, could overflow int64 due to percentage conversion ONLY
+			absUsed[resourceName] = 100
 		default:
-			div := (float64(usedResource) / float64(capResource)) * 100
-			// we really do not want to show a percentage value that is larger than a 32-bit integer.
-			// even that is already really large and could easily lead to UI render issues.
-			if div > float64(math.MaxInt32) {
-				absResValue = math.MaxInt32
-			} else {
-				absResValue = int64(div)
-			}
+			absUsed[resourceName] = (float64(usedResource) / float64(capResource)) * 100
 		}
-		absResource.Resources[resourceName] = Quantity(absResValue)
 	}
 	if missingResources.Len() != 0 {
 		log.Log(log.Resources).Debug("Absolute usage result is missing resource information",
 			zap.Stringer("missing resource(s)", missingResources))
 	}
-	return absResource
+	return absUsed
+}
+
+// PercentOf returns the used fraction, a value between 0 and 1 if the usage does not exceed the total, for each
+// type defined in the resource it is called on. The edge cases are handled the same as CalculateAbsUsedCapacity:
+// If usage is 0 or below 0, the fraction is always 0
+// if total is 0 or below 0, or not defined, the fraction is always 1
+// A nil resource returns an empty map.
+func (r *Resource) PercentOf(total *Resource) map[string]float64 {
+	fractions := make(map[string]float64)
+	if r == nil {
+		return fractions
+	}
+	if total == nil {
+		total = Zero
+	}
+	for k, v := range r.Resources {
+		totalVal := total.Resources[k]
+		switch {
+		case v <= 0:
+			fractions[k] = 0
+		case totalVal <= 0:
+			fractions[k] = 1
+		default:
+			fractions[k] = float64(v) / float64(totalVal)
+		}
+	}
+	return fractions
+}
+
+// ThresholdExceeded returns true if the absolute used percentage for any resource named in the capacity is equal to
+// or larger than the percentage passed in. The type name returned is the first type, in sorted order, that exceeds
+// the threshold. The absolute used percentage is calculated by CalculateAbsUsedCapacityFloat.
+// Resources types not defined in the usage do not exceed the threshold.
+func ThresholdExceeded(capacity, used *Resource, percent float64) (bool, string) {
+	absUsed := CalculateAbsUsedCapacityFloat(capacity, used)
+	keys := make([]string, 0, len(absUsed))
+	for k := range absUsed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if absUsed[k] >= percent {
+			return true, k
+		}
+	}
+	return false, ""
+}
+
+// RemainingPercent returns the remaining capacity as a percentage, without truncation, for each type defined in the
+// capacity: (capacity - used) / capacity * 100. The percentage is capped to the range [0,100].
+// Types not defined in used are considered 0 and have 100% remaining, a nil used is treated as an empty resource.
+// if capacity is 0 or below 0, remaining is always 0
+// A nil capacity returns an empty map.
+func RemainingPercent(capacity, used *Resource) map[string]float64 {
+	remaining := make(map[string]float64)
+	if capacity == nil {
+		return remaining
+	}
+	if used == nil {
+		used = Zero // shadows in the local function not seen by the callers.
+	}
+	for k, capVal := range capacity.Resources {
+		if capVal <= 0 {
+			remaining[k] = 0
+			continue
+		}
+		free := float64(subVal(capVal, used.Resources[k])) / float64(capVal) * 100
+		remaining[k] = max(0, min(free, 100))
+	}
+	return remaining
 }
 
 // DominantResourceType calculates the most used resource type based on the ratio of used compared to
@@ -1136,18 +3228,7 @@ func (r *Resource) DominantResourceType(capacity *Resource) string {
 				zap.String("missing resource", name))
 			continue
 		}
-		// calculate the ratio between usage and capacity
-		// ratio should be somewhere between 0 and 1, but do not restrict
-		// handle 0 values specifically just to be safe should never happen
-		if capVal == 0 {
-			if usedVal == 0 {
-				temp = 0 // no usage, no cap: consider empty
-			} else {
-				temp = 1 // usage, no cap: fully used
-			}
-		} else {
-			temp = float64(usedVal) / float64(capVal) // both not zero calculate ratio
-		}
+		temp = usageRatio(usedVal, capVal)
 		// if we have exactly the same use the latest one
 		if temp >= div {
 			div = temp
@@ -1156,3 +3237,112 @@ func (r *Resource) DominantResourceType(capacity *Resource) string {
 	}
 	return dominant
 }
+
+// RankedResourceTypes returns the resource types ordered from the most to the least used based on the ratio of
+// used compared to the capacity. The ratio is calculated using the same rules as DominantResourceType.
+// Types with the same ratio are sorted alphabetically.
+// Ignores resources types that are used but not defined in the capacity.
+// A nil resource or capacity returns an empty list.
+func (r *Resource) RankedResourceTypes(capacity *Resource) []string {
+	ranked := make([]string, 0)
+	if r == nil || capacity == nil {
+		return ranked
+	}
+	ratios := make(map[string]float64)
+	for name, usedVal := range r.Resources {
+		capVal, ok := capacity.Resources[name]
+		if !ok {
+			log.Log(log.Resources).Debug("missing resource in ranked calculation",
+				zap.String("missing resource", name))
+			continue
+		}
+		ratios[name] = usageRatio(usedVal, capVal)
+		ranked = append(ranked, name)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ratios[ranked[i]] != ratios[ranked[j]] {
+			return ratios[ranked[i]] > ratios[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+	return ranked
+}
+
+// Skew returns the difference between the largest and the smallest ratio of used compared to the capacity over the
+// resource types. The ratio is calculated using the same rules as DominantResourceType. A request that uses the same
+// share of each type has a skew of 0, a larger skew means the request is less balanced.
+// Ignores resources types that are used but not defined in the capacity.
+// A nil resource or capacity, or less than two types to compare, returns 0.
+func (r *Resource) Skew(capacity *Resource) float64 {
+	if r == nil || capacity == nil {
+		return 0
+	}
+	count := 0
+	var lowest, highest float64
+	for name, usedVal := range r.Resources {
+		capVal, ok := capacity.Resources[name]
+		if !ok {
+			continue
+		}
+		ratio := usageRatio(usedVal, capVal)
+		if count == 0 {
+			lowest, highest = ratio, ratio
+		} else {
+			lowest = min(lowest, ratio)
+			highest = max(highest, ratio)
+		}
+		count++
+	}
+	if count < 2 {
+		return 0
+	}
+	return highest - lowest
+}
+
+// SortByDominantShare sorts the resources in place in ascending order of their dominant share of the capacity.
+// The dominant share is the usage ratio of the type returned by DominantResourceType, a resource without a dominant
+// type has a share of 0. Resources with the same dominant share are sorted on their L1Norm in ascending order.
+// The sort is stable: resources that have the same share and norm keep their relative order.
+func SortByDominantShare(resources []*Resource, capacity *Resource) {
+	type sortEntry struct {
+		res   *Resource
+		share float64
+		norm  int64
+	}
+	entries := make([]sortEntry, len(resources))
+	for i, res := range resources {
+		entries[i] = sortEntry{res: res, share: res.dominantShare(capacity), norm: res.L1Norm()}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].share != entries[j].share {
+			return entries[i].share < entries[j].share
+		}
+		return entries[i].norm < entries[j].norm
+	})
+	for i := range entries {
+		resources[i] = entries[i].res
+	}
+}
+
+// dominantShare returns the usage ratio of the dominant resource type compared to the capacity.
+// Returns 0 if there is no dominant type.
+func (r *Resource) dominantShare(capacity *Resource) float64 {
+	dominant := r.DominantResourceType(capacity)
+	if dominant == "" {
+		return 0
+	}
+	return usageRatio(r.Resources[dominant], capacity.Resources[dominant])
+}
+
+// usageRatio calculates the ratio between usage and capacity
+// ratio should be somewhere between 0 and 1, but do not restrict
+// handle 0 values specifically just to be safe should never happen
+func usageRatio(usedVal, capVal Quantity) float64 {
+	if capVal == 0 {
+		if usedVal == 0 {
+			return 0 // no usage, no cap: consider empty
+		}
+		return 1 // usage, no cap: fully used
+	}
+	return float64(usedVal) / float64(capVal) // both not zero calculate ratio
+}
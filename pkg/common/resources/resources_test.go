@@ -19,15 +19,21 @@
 package resources
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
+	"sort"
 	"testing"
 
 	"golang.org/x/exp/maps"
+	"gopkg.in/yaml.v3"
 	"gotest.tools/v3/assert"
 
 	"github.com/apache/yunikorn-scheduler-interface/lib/go/common"
+	"github.com/apache/yunikorn-scheduler-interface/lib/go/si"
 )
 
 func CheckLenOfResource(res *Resource, expected int) (bool, string) {
@@ -118,6 +124,8 @@ func TestNewResourceFromConf(t *testing.T) {
 		{"vcore multipliers with \"k\"", map[string]string{"vcore": "10k"}, expectedvalues{true, 1, "map[vcore:10000000]"}},
 		{"vcore multipliers", map[string]string{"vcore": "10"}, expectedvalues{true, 1, "map[vcore:10000]"}},
 		{"vcore multipliers with \"m\"", map[string]string{"vcore": "10m"}, expectedvalues{true, 1, "map[vcore:10]"}},
+		{"vcore fractional", map[string]string{"vcore": "0.5"}, expectedvalues{true, 1, "map[vcore:500]"}},
+		{"failure case: fractional memory", map[string]string{"memory": "0.5"}, expectedvalues{false, 0, ""}},
 		{"failure case: parse error", map[string]string{"fail": "xx"}, expectedvalues{false, 0, ""}},
 		{"negative resource", map[string]string{"memory": "-15"}, expectedvalues{false, 0, ""}},
 		{"nagative resource for vcore", map[string]string{"vcore": "-15"}, expectedvalues{false, 0, ""}},
@@ -144,6 +152,38 @@ func TestNewResourceFromConf(t *testing.T) {
 	}
 }
 
+func TestNewResourceFromConfLimited(t *testing.T) {
+	conf := map[string]string{common.Memory: "10", common.CPU: "1", "pods": "10"}
+	tests := map[string]struct {
+		input    map[string]string
+		maxTypes int
+		err      string
+		expected map[string]Quantity
+	}{
+		"nil input":          {input: nil, maxTypes: 0, expected: map[string]Quantity{}},
+		"empty input":        {input: map[string]string{}, maxTypes: -1, expected: map[string]Quantity{}},
+		"below limit":        {input: conf, maxTypes: 5, expected: map[string]Quantity{common.Memory: 10, common.CPU: 1000, "pods": 10}},
+		"at limit":           {input: conf, maxTypes: 3, expected: map[string]Quantity{common.Memory: 10, common.CPU: 1000, "pods": 10}},
+		"above limit":        {input: conf, maxTypes: 2, err: "too many resource types: 3 defined, maximum allowed 2"},
+		"zero limit":         {input: conf, maxTypes: 0, err: "too many resource types: 3 defined, maximum allowed 0"},
+		"negative limit":     {input: conf, maxTypes: -5, err: "too many resource types: 3 defined, maximum allowed 0"},
+		"parse error":        {input: map[string]string{"fail": "xx"}, maxTypes: 1, err: "invalid quantity"},
+		"limit before parse": {input: map[string]string{"fail": "xx", "other": "xx"}, maxTypes: 1, err: "too many resource types"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res, err := NewResourceFromConfLimited(tt.input, tt.maxTypes)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				assert.Assert(t, res == nil, "no resource expected on error")
+				return
+			}
+			assert.NilError(t, err, "no error expected")
+			assert.DeepEqual(t, res.Resources, tt.expected)
+		})
+	}
+}
+
 func TestCloneNil(t *testing.T) {
 	// make sure we're nil safe IDE will complain about the non nil check
 	defer func() {
@@ -219,6 +259,88 @@ func TestEquals(t *testing.T) {
 	}
 }
 
+func TestEqualsIgnoring(t *testing.T) {
+	ignore := []string{"__reserved__", "internal"}
+	same := NewResourceFromMap(map[string]Quantity{"first": 1})
+	tests := map[string]struct {
+		left     *Resource
+		right    *Resource
+		ignore   []string
+		expected bool
+	}{
+		"nil inputs":             {left: nil, right: nil, ignore: ignore, expected: true},
+		"nil left":               {left: nil, right: NewResource(), ignore: ignore, expected: false},
+		"nil right":              {left: NewResource(), right: nil, ignore: ignore, expected: false},
+		"same object":            {left: same, right: same, ignore: nil, expected: true},
+		"nil ignore":             {left: NewResourceFromMap(map[string]Quantity{"first": 1, "internal": 1}), right: NewResourceFromMap(map[string]Quantity{"first": 1}), ignore: nil, expected: false},
+		"ignored differs":        {left: NewResourceFromMap(map[string]Quantity{"first": 1, "internal": 1}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "internal": 2}), ignore: ignore, expected: true},
+		"ignored one side":       {left: NewResourceFromMap(map[string]Quantity{"first": 1, "__reserved__": 5}), right: NewResourceFromMap(map[string]Quantity{"first": 1}), ignore: ignore, expected: true},
+		"ignored only":           {left: NewResourceFromMap(map[string]Quantity{"internal": 1}), right: NewResourceFromMap(map[string]Quantity{"__reserved__": 1}), ignore: ignore, expected: true},
+		"other type differs":     {left: NewResourceFromMap(map[string]Quantity{"first": 1, "internal": 1}), right: NewResourceFromMap(map[string]Quantity{"first": 2, "internal": 1}), ignore: ignore, expected: false},
+		"zero same as undefined": {left: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 0}), right: NewResourceFromMap(map[string]Quantity{"first": 1}), ignore: ignore, expected: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, EqualsIgnoring(tt.left, tt.right, tt.ignore), tt.expected, "unexpected comparison result")
+		})
+	}
+}
+
+func TestEqualsWithinTolerance(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right *Resource
+		tolerance   Quantity
+		expected    bool
+	}{
+		{"both nil", nil, nil, 0, true},
+		{"nil left", nil, NewResource(), 10, false},
+		{"nil right", NewResource(), nil, 10, false},
+		{"both empty", NewResource(), NewResource(), 0, true},
+		{"equal", NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 10}), 0, true},
+		{"within tolerance", NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), NewResourceFromMap(map[string]Quantity{"first": 11, "second": 4}), 1, true},
+		{"outside tolerance", NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), NewResourceFromMap(map[string]Quantity{"first": 12, "second": 5}), 1, false},
+		{"missing type within tolerance", NewResourceFromMap(map[string]Quantity{"first": 10, "second": 1}), NewResourceFromMap(map[string]Quantity{"first": 10}), 1, true},
+		{"missing type outside tolerance", NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 10, "second": 2}), 1, false},
+		{"negative tolerance", NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 10}), -1, true},
+		{"negative tolerance differs", NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 11}), -1, false},
+		{"extreme values", NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), NewResourceFromMap(map[string]Quantity{"first": math.MinInt64}), math.MaxInt64, false},
+		{"extreme tolerance", NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), NewResourceFromMap(map[string]Quantity{"first": -1}), math.MaxInt64, false},
+		{"extreme tolerance within", NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), NewResourceFromMap(map[string]Quantity{"first": 0}), math.MaxInt64, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, EqualsWithinTolerance(tt.left, tt.right, tt.tolerance), tt.expected)
+			assert.Equal(t, EqualsWithinTolerance(tt.right, tt.left, tt.tolerance), tt.expected)
+		})
+	}
+}
+
+func TestEqualsIgnoringZeros(t *testing.T) {
+	tests := map[string]struct {
+		left     *Resource
+		right    *Resource
+		expected bool
+	}{
+		"nil inputs":         {left: nil, right: nil, expected: true},
+		"nil and empty":      {left: nil, right: NewResource(), expected: true},
+		"nil and zero":       {left: nil, right: NewResourceFromMap(map[string]Quantity{"first": 0}), expected: true},
+		"zero and nil":       {left: NewResourceFromMap(map[string]Quantity{"first": 0, "second": 0}), right: nil, expected: true},
+		"nil and value":      {left: nil, right: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: false},
+		"pruned":             {left: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 0}), right: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: true},
+		"pruned right":       {left: NewResourceFromMap(map[string]Quantity{"first": 1}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 0}), expected: true},
+		"different zeros":    {left: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 0}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "third": 0}), expected: true},
+		"different values":   {left: NewResourceFromMap(map[string]Quantity{"first": 1}), right: NewResourceFromMap(map[string]Quantity{"first": 2}), expected: false},
+		"missing type":       {left: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}), right: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: false},
+		"missing type right": {left: NewResourceFromMap(map[string]Quantity{"first": 1}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "second": -1}), expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, EqualsIgnoringZeros(tt.left, tt.right), tt.expected, "unexpected comparison result")
+		})
+	}
+}
+
 func TestIsZero(t *testing.T) {
 	var tests = []struct {
 		caseName string
@@ -272,6 +394,201 @@ func TestStrictlyGreaterThanZero(t *testing.T) {
 	}
 }
 
+func TestScaleDimension(t *testing.T) {
+	tests := map[string]struct {
+		res      *Resource
+		key      string
+		ratio    float64
+		expected *Resource
+	}{
+		"nil resource":   {res: nil, key: "first", ratio: 2, expected: nil},
+		"empty resource": {res: NewResource(), key: "first", ratio: 2, expected: NewResource()},
+		"missing key":    {res: NewResourceFromMap(map[string]Quantity{"first": 10}), key: "second", ratio: 2, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"double":         {res: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}), key: "first", ratio: 2, expected: NewResourceFromMap(map[string]Quantity{"first": 20, "second": 10})},
+		"round down":     {res: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}), key: "second", ratio: 0.25, expected: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 2})},
+		"zero ratio":     {res: NewResourceFromMap(map[string]Quantity{"first": 10}), key: "first", ratio: 0, expected: NewResourceFromMap(map[string]Quantity{"first": 0})},
+		"overflow":       {res: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64 / 2}), key: "first", ratio: 4, expected: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var original *Resource
+			if tt.res != nil {
+				original = tt.res.Clone()
+			}
+			result := tt.res.ScaleDimension(tt.key, tt.ratio)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+			assert.Assert(t, DeepEquals(tt.res, original), "input resource was changed")
+		})
+	}
+}
+
+func TestQuantizeUp(t *testing.T) {
+	step := NewResourceFromMap(map[string]Quantity{"gpu": 2, "hugepages": 1024, "zero": 0, "negative": -4})
+	tests := map[string]struct {
+		res      *Resource
+		step     *Resource
+		expected *Resource
+	}{
+		"nil resource":   {res: nil, step: step, expected: nil},
+		"nil step":       {res: NewResourceFromMap(map[string]Quantity{"gpu": 3}), step: nil, expected: NewResourceFromMap(map[string]Quantity{"gpu": 3})},
+		"empty resource": {res: NewResource(), step: step, expected: NewResource()},
+		"multiple":       {res: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 2048}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 2048})},
+		"round up":       {res: NewResourceFromMap(map[string]Quantity{"gpu": 3, "hugepages": 1}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 1024})},
+		"zero value":     {res: NewResourceFromMap(map[string]Quantity{"gpu": 0}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 0})},
+		"negative value": {res: NewResourceFromMap(map[string]Quantity{"gpu": -3, "hugepages": -1025}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": -2, "hugepages": -1024})},
+		"no step":        {res: NewResourceFromMap(map[string]Quantity{"other": 3}), step: step, expected: NewResourceFromMap(map[string]Quantity{"other": 3})},
+		"invalid step":   {res: NewResourceFromMap(map[string]Quantity{"zero": 3, "negative": 3}), step: step, expected: NewResourceFromMap(map[string]Quantity{"zero": 3, "negative": 3})},
+		"overflow":       {res: NewResourceFromMap(map[string]Quantity{"hugepages": math.MaxInt64 - 1}), step: step, expected: NewResourceFromMap(map[string]Quantity{"hugepages": math.MaxInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.res.QuantizeUp(tt.step)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestQuantizeDown(t *testing.T) {
+	step := NewResourceFromMap(map[string]Quantity{"gpu": 2, "hugepages": 1024, "zero": 0, "negative": -4, "triple": 3})
+	tests := map[string]struct {
+		res      *Resource
+		step     *Resource
+		expected *Resource
+	}{
+		"nil resource":   {res: nil, step: step, expected: nil},
+		"nil step":       {res: NewResourceFromMap(map[string]Quantity{"gpu": 3}), step: nil, expected: NewResourceFromMap(map[string]Quantity{"gpu": 3})},
+		"empty resource": {res: NewResource(), step: step, expected: NewResource()},
+		"multiple":       {res: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 2048}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 2048})},
+		"round down":     {res: NewResourceFromMap(map[string]Quantity{"gpu": 3, "hugepages": 1023}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 2, "hugepages": 0})},
+		"zero value":     {res: NewResourceFromMap(map[string]Quantity{"gpu": 0}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 0})},
+		"negative value": {res: NewResourceFromMap(map[string]Quantity{"gpu": -3, "hugepages": -1}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": -4, "hugepages": -1024})},
+		"no step":        {res: NewResourceFromMap(map[string]Quantity{"other": 3}), step: step, expected: NewResourceFromMap(map[string]Quantity{"other": 3})},
+		"invalid step":   {res: NewResourceFromMap(map[string]Quantity{"zero": 3, "negative": 3}), step: step, expected: NewResourceFromMap(map[string]Quantity{"zero": 3, "negative": 3})},
+		"overflow":       {res: NewResourceFromMap(map[string]Quantity{"triple": math.MinInt64}), step: step, expected: NewResourceFromMap(map[string]Quantity{"triple": math.MinInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.res.QuantizeDown(tt.step)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestQuantizeNearest(t *testing.T) {
+	step := NewResourceFromMap(map[string]Quantity{"gpu": 2, "hugepages": 1024, "zero": 0, "negative": -4, "triple": 3})
+	tests := map[string]struct {
+		res      *Resource
+		step     *Resource
+		expected *Resource
+	}{
+		"nil resource":      {res: nil, step: step, expected: nil},
+		"nil step":          {res: NewResourceFromMap(map[string]Quantity{"gpu": 3}), step: nil, expected: NewResourceFromMap(map[string]Quantity{"gpu": 3})},
+		"empty resource":    {res: NewResource(), step: step, expected: NewResource()},
+		"multiple":          {res: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 2048}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 2048})},
+		"round down":        {res: NewResourceFromMap(map[string]Quantity{"hugepages": 1535, "triple": 4}), step: step, expected: NewResourceFromMap(map[string]Quantity{"hugepages": 1024, "triple": 3})},
+		"round up":          {res: NewResourceFromMap(map[string]Quantity{"hugepages": 1537, "triple": 5}), step: step, expected: NewResourceFromMap(map[string]Quantity{"hugepages": 2048, "triple": 6})},
+		"half up":           {res: NewResourceFromMap(map[string]Quantity{"gpu": 3, "hugepages": 512}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": 4, "hugepages": 1024})},
+		"negative half up":  {res: NewResourceFromMap(map[string]Quantity{"gpu": -3, "hugepages": -512}), step: step, expected: NewResourceFromMap(map[string]Quantity{"gpu": -2, "hugepages": 0})},
+		"negative nearest":  {res: NewResourceFromMap(map[string]Quantity{"triple": -4, "hugepages": -1000}), step: step, expected: NewResourceFromMap(map[string]Quantity{"triple": -3, "hugepages": -1024})},
+		"no step":           {res: NewResourceFromMap(map[string]Quantity{"other": 3}), step: step, expected: NewResourceFromMap(map[string]Quantity{"other": 3})},
+		"invalid step":      {res: NewResourceFromMap(map[string]Quantity{"zero": 3, "negative": 3}), step: step, expected: NewResourceFromMap(map[string]Quantity{"zero": 3, "negative": 3})},
+		"overflow":          {res: NewResourceFromMap(map[string]Quantity{"hugepages": math.MaxInt64 - 1}), step: step, expected: NewResourceFromMap(map[string]Quantity{"hugepages": math.MaxInt64})},
+		"negative overflow": {res: NewResourceFromMap(map[string]Quantity{"triple": math.MinInt64}), step: step, expected: NewResourceFromMap(map[string]Quantity{"triple": math.MinInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.res.QuantizeNearest(tt.step)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestLerp(t *testing.T) {
+	tests := map[string]struct {
+		start    *Resource
+		end      *Resource
+		t        float64
+		expected *Resource
+	}{
+		"nil inputs":     {nil, nil, 0.5, NewResource()},
+		"nil start":      {nil, NewResourceFromMap(map[string]Quantity{"first": 10}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 5})},
+		"nil end":        {NewResourceFromMap(map[string]Quantity{"first": 10}), nil, 0.5, NewResourceFromMap(map[string]Quantity{"first": 5})},
+		"start":          {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 0, NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"end":            {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 1, NewResourceFromMap(map[string]Quantity{"first": 20})},
+		"halfway":        {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 15})},
+		"decreasing":     {NewResourceFromMap(map[string]Quantity{"first": 20}), NewResourceFromMap(map[string]Quantity{"first": 10}), 0.25, NewResourceFromMap(map[string]Quantity{"first": 17})},
+		"rounded down":   {NewResourceFromMap(map[string]Quantity{"first": 10, "second": 0}), NewResourceFromMap(map[string]Quantity{"first": 0, "second": 10}), 0.55, NewResourceFromMap(map[string]Quantity{"first": 4, "second": 5})},
+		"below zero t":   {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), -1, NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"above one t":    {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 2, NewResourceFromMap(map[string]Quantity{"first": 20})},
+		"union of types": {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"second": 10}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 5, "second": 5})},
+		"overflow":       {NewResourceFromMap(map[string]Quantity{"first": math.MinInt64 + 1}), NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 0})},
+		"max to max":     {NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), 0.5, NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Lerp(tt.start, tt.end, tt.t)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestBlend(t *testing.T) {
+	tests := map[string]struct {
+		oldRes   *Resource
+		newRes   *Resource
+		alpha    float64
+		expected *Resource
+	}{
+		"nil inputs":     {nil, nil, 0.5, NewResource()},
+		"nil old":        {nil, NewResourceFromMap(map[string]Quantity{"first": 10}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 5})},
+		"nil new":        {NewResourceFromMap(map[string]Quantity{"first": 10}), nil, 0.25, NewResourceFromMap(map[string]Quantity{"first": 7})},
+		"alpha zero":     {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 0, NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"alpha one":      {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 1, NewResourceFromMap(map[string]Quantity{"first": 20})},
+		"blend":          {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 0.25, NewResourceFromMap(map[string]Quantity{"first": 12})},
+		"floor negative": {NewResourceFromMap(map[string]Quantity{"first": -10}), NewResourceFromMap(map[string]Quantity{"first": -20}), 0.25, NewResourceFromMap(map[string]Quantity{"first": -13})},
+		"alpha clamped":  {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 5, NewResourceFromMap(map[string]Quantity{"first": 20})},
+		"negative alpha": {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), -5, NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"union of types": {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"second": 10}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 5, "second": 5})},
+		"maximum":        {NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), 0.5, NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64})},
+		"minimum":        {NewResourceFromMap(map[string]Quantity{"first": math.MinInt64}), NewResourceFromMap(map[string]Quantity{"first": math.MinInt64}), 0.5, NewResourceFromMap(map[string]Quantity{"first": math.MinInt64})},
+		"exact old":      {NewResourceFromMap(map[string]Quantity{"first": 1<<62 + 1}), nil, 0, NewResourceFromMap(map[string]Quantity{"first": 1<<62 + 1})},
+		"exact new":      {nil, NewResourceFromMap(map[string]Quantity{"first": 1<<62 + 1}), 1, NewResourceFromMap(map[string]Quantity{"first": 1<<62 + 1})},
+		"large values":   {NewResourceFromMap(map[string]Quantity{"first": 1<<62 + 1}), NewResourceFromMap(map[string]Quantity{"first": 1<<62 + 5}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 1<<62 + 3})},
+		"near new":       {NewResourceFromMap(map[string]Quantity{"first": 0}), NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), 0.9999999999999999, NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64 - 1023})},
+		"extremes":       {NewResourceFromMap(map[string]Quantity{"first": math.MinInt64}), NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), 0.5, NewResourceFromMap(map[string]Quantity{"first": 0})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Blend(tt.oldRes, tt.newRes, tt.alpha)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestGrowthRate(t *testing.T) {
+	tests := map[string]struct {
+		earlier  *Resource
+		later    *Resource
+		seconds  float64
+		expected map[string]float64
+	}{
+		"nil inputs":       {nil, nil, 10, map[string]float64{}},
+		"nil earlier":      {nil, NewResourceFromMap(map[string]Quantity{"first": 10}), 5, map[string]float64{"first": 2}},
+		"nil later":        {NewResourceFromMap(map[string]Quantity{"first": 10}), nil, 5, map[string]float64{"first": -2}},
+		"zero seconds":     {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), 0, map[string]float64{}},
+		"negative seconds": {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 20}), -1, map[string]float64{}},
+		"growth":           {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 15}), 2, map[string]float64{"first": 2.5}},
+		"unchanged":        {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"first": 10}), 2, map[string]float64{"first": 0}},
+		"union of types":   {NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"second": 10}), 10, map[string]float64{"first": -1, "second": 1}},
+		"no overflow":      {NewResourceFromMap(map[string]Quantity{"first": math.MinInt64}), NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), 1, map[string]float64{"first": math.Exp2(64)}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, GrowthRate(tt.earlier, tt.later, tt.seconds), tt.expected)
+		})
+	}
+}
+
 func TestStrictlyGreaterThan(t *testing.T) {
 	type inputs struct {
 		larger  map[string]Quantity
@@ -316,6 +633,8 @@ func TestStrictlyGreaterThan(t *testing.T) {
 			if result := StrictlyGreaterThan(base, compare); result != tt.expected.smaller {
 				t.Errorf("base %v, compare %v, got %v, expeceted %v", base, compare, result, tt.expected.smaller)
 			}
+			assert.Equal(t, StrictlyLessThan(base, compare), tt.expected.larger, "less than is not the mirror of greater than")
+			assert.Equal(t, StrictlyLessThan(compare, base), tt.expected.smaller, "less than is not the mirror of greater than")
 		})
 	}
 }
@@ -365,6 +684,153 @@ func TestMatchAnyOnlyExisting(t *testing.T) {
 	assert.Assert(t, result)
 }
 
+func TestIsSubsetOf(t *testing.T) {
+	var tests = []struct {
+		caseName string
+		left     map[string]Quantity
+		right    map[string]Quantity
+		expected bool
+	}{
+		{"nil resource is subset of nil", nil, nil, true},
+		{"nil resource is subset of set resource", nil, map[string]Quantity{"first": 1}, true},
+		{"empty resource is subset of nil", map[string]Quantity{}, nil, true},
+		{"empty resource is subset of empty", map[string]Quantity{}, map[string]Quantity{}, true},
+		{"set resource is not subset of nil", map[string]Quantity{"first": 1}, nil, false},
+		{"set resource is not subset of empty", map[string]Quantity{"first": 1}, map[string]Quantity{}, false},
+		{"same types different values", map[string]Quantity{"first": 1}, map[string]Quantity{"first": 20}, true},
+		{"zero values are types", map[string]Quantity{"zero": 0}, map[string]Quantity{"zero": 0}, true},
+		{"extra type in other", map[string]Quantity{"first": 10}, map[string]Quantity{"first": 10, "second": 1}, true},
+		{"extra type in resource", map[string]Quantity{"first": 10, "second": 1}, map[string]Quantity{"first": 10}, false},
+		{"disjoint types", map[string]Quantity{"first": 10}, map[string]Quantity{"second": 10}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caseName, func(t *testing.T) {
+			var left *Resource
+			var right *Resource
+			if tt.left != nil {
+				left = NewResourceFromMap(tt.left)
+			}
+			if tt.right != nil {
+				right = NewResourceFromMap(tt.right)
+			}
+			assert.Equal(t, left.IsSubsetOf(right), tt.expected, "unexpected subset result")
+		})
+	}
+}
+
+func TestTypeUnionIntersection(t *testing.T) {
+	tests := map[string]struct {
+		left         *Resource
+		right        *Resource
+		union        []string
+		intersection []string
+	}{
+		"nil inputs":   {left: nil, right: nil, union: []string{}, intersection: []string{}},
+		"nil left":     {left: nil, right: NewResourceFromMap(map[string]Quantity{"b": 1, "a": 0}), union: []string{"a", "b"}, intersection: []string{}},
+		"nil right":    {left: NewResourceFromMap(map[string]Quantity{"b": 1, "a": 0}), right: nil, union: []string{"a", "b"}, intersection: []string{}},
+		"same types":   {left: NewResourceFromMap(map[string]Quantity{"a": 1, "b": 1}), right: NewResourceFromMap(map[string]Quantity{"b": 5, "a": 0}), union: []string{"a", "b"}, intersection: []string{"a", "b"}},
+		"overlap":      {left: NewResourceFromMap(map[string]Quantity{"c": 1, "a": 1}), right: NewResourceFromMap(map[string]Quantity{"b": 1, "c": 2}), union: []string{"a", "b", "c"}, intersection: []string{"c"}},
+		"no overlap":   {left: NewResourceFromMap(map[string]Quantity{"a": 1}), right: NewResourceFromMap(map[string]Quantity{"b": 1}), union: []string{"a", "b"}, intersection: []string{}},
+		"empty inputs": {left: NewResource(), right: NewResource(), union: []string{}, intersection: []string{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, TypeUnion(tt.left, tt.right), tt.union)
+			assert.DeepEqual(t, TypeIntersection(tt.left, tt.right), tt.intersection)
+			assert.DeepEqual(t, TypeUnion(tt.right, tt.left), tt.union)
+			assert.DeepEqual(t, TypeIntersection(tt.right, tt.left), tt.intersection)
+		})
+	}
+}
+
+func TestValidateTypes(t *testing.T) {
+	allowed := map[string]bool{common.CPU: true, common.Memory: true, "disabled": false}
+	tests := map[string]struct {
+		res     *Resource
+		allowed map[string]bool
+		err     string
+	}{
+		"nil resource":           {res: nil, allowed: allowed},
+		"nil resource nil set":   {res: nil, allowed: nil},
+		"empty resource":         {res: NewResource(), allowed: map[string]bool{}},
+		"allowed types":          {res: NewResourceFromMap(map[string]Quantity{common.CPU: 1, common.Memory: 0}), allowed: allowed},
+		"empty allowed set":      {res: NewResourceFromMap(map[string]Quantity{common.CPU: 1}), allowed: map[string]bool{}, err: "resource type not allowed: vcore"},
+		"nil allowed set":        {res: NewResourceFromMap(map[string]Quantity{common.CPU: 1}), allowed: nil, err: "resource type not allowed: vcore"},
+		"single unknown type":    {res: NewResourceFromMap(map[string]Quantity{common.CPU: 1, "gpu": 1}), allowed: allowed, err: "resource type not allowed: gpu"},
+		"multiple unknown types": {res: NewResourceFromMap(map[string]Quantity{"gpu": 1, common.Memory: 1, "fpga": 1}), allowed: allowed, err: "resource type not allowed: fpga, gpu"},
+		"disabled type":          {res: NewResourceFromMap(map[string]Quantity{"disabled": 1}), allowed: allowed, err: "resource type not allowed: disabled"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.res.ValidateTypes(test.allowed)
+			if test.err != "" {
+				assert.Error(t, err, test.err)
+			} else {
+				assert.NilError(t, err, "unexpected validation error")
+			}
+		})
+	}
+}
+
+func TestValidateBounds(t *testing.T) {
+	bounds := ResourceBounds{
+		common.CPU:    {Min: 100, Max: 4000},
+		common.Memory: {Min: 1024, Max: 1024 * 1024},
+		"fixed":       {Min: 1, Max: 1},
+	}
+	tests := map[string]struct {
+		res    *Resource
+		bounds ResourceBounds
+		err    string
+	}{
+		"nil resource":      {res: nil, bounds: bounds},
+		"nil bounds":        {res: NewResourceFromMap(map[string]Quantity{common.CPU: 1}), bounds: nil},
+		"empty resource":    {res: NewResource(), bounds: bounds},
+		"within bounds":     {res: NewResourceFromMap(map[string]Quantity{common.CPU: 100, common.Memory: 1024 * 1024, "fixed": 1}), bounds: bounds},
+		"no bound for type": {res: NewResourceFromMap(map[string]Quantity{"other": -100}), bounds: bounds},
+		"too low":           {res: NewResourceFromMap(map[string]Quantity{common.CPU: 99}), bounds: bounds, err: "resource out of bounds: vcore (99 below minimum 100)"},
+		"too high":          {res: NewResourceFromMap(map[string]Quantity{common.Memory: 1024*1024 + 1}), bounds: bounds, err: "resource out of bounds: memory (1048577 above maximum 1048576)"},
+		"multiple": {res: NewResourceFromMap(map[string]Quantity{common.CPU: 5000, common.Memory: 1024, "fixed": 0}), bounds: bounds,
+			err: "resource out of bounds: fixed (0 below minimum 1), vcore (5000 above maximum 4000)"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.res.ValidateBounds(tt.bounds)
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+			} else {
+				assert.NilError(t, err, "unexpected validation error")
+			}
+		})
+	}
+}
+
+func TestIsFullyDefined(t *testing.T) {
+	required := []string{common.CPU, common.Memory, "pods"}
+	tests := map[string]struct {
+		res      *Resource
+		required []string
+		defined  bool
+		missing  []string
+	}{
+		"nil resource":       {res: nil, required: required, defined: false, missing: required},
+		"nil resource empty": {res: nil, required: nil, defined: false, missing: []string{}},
+		"empty required":     {res: NewResource(), required: nil, defined: true, missing: []string{}},
+		"empty resource":     {res: NewResource(), required: required, defined: false, missing: required},
+		"all defined":        {res: NewResourceFromMap(map[string]Quantity{common.CPU: 1, common.Memory: 1, "pods": 1}), required: required, defined: true, missing: []string{}},
+		"zero value defined": {res: NewResourceFromMap(map[string]Quantity{common.CPU: 0, common.Memory: 1, "pods": 0}), required: required, defined: true, missing: []string{}},
+		"extra types":        {res: NewResourceFromMap(map[string]Quantity{common.CPU: 1, common.Memory: 1, "pods": 1, "gpu": 1}), required: required, defined: true, missing: []string{}},
+		"missing types":      {res: NewResourceFromMap(map[string]Quantity{common.Memory: 1, "gpu": 1}), required: required, defined: false, missing: []string{common.CPU, "pods"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			defined, missing := test.res.IsFullyDefined(test.required)
+			assert.Equal(t, defined, test.defined, "unexpected defined result")
+			assert.DeepEqual(t, missing, test.missing)
+		})
+	}
+}
+
 func TestStrictlyGreaterThanOnlyExisting(t *testing.T) {
 	type inputs struct {
 		larger  map[string]Quantity
@@ -471,6 +937,8 @@ func TestStrictlyGreaterThanOrEquals(t *testing.T) {
 			if result != tt.expected[1] {
 				t.Errorf("base %v, compare %v, got %v, expeceted %v", base, compare, result, tt.expected[1])
 			}
+			assert.Equal(t, StrictlyLessThanOrEquals(base, compare), tt.expected[0], "less than or equals is not the mirror of greater than or equals")
+			assert.Equal(t, StrictlyLessThanOrEquals(compare, base), tt.expected[1], "less than or equals is not the mirror of greater than or equals")
 		})
 	}
 }
@@ -500,6 +968,34 @@ func TestComponentWiseMin(t *testing.T) {
 	}
 }
 
+func TestComponentWiseMinWeighted(t *testing.T) {
+	smallerRes := NewResourceFromMap(map[string]Quantity{"first": 5, "second": 15, "third": 6})
+	higherRes := NewResourceFromMap(map[string]Quantity{"first": 7, "second": 10, "forth": 6})
+	prefer := map[string]bool{"first": true, "third": true}
+	expected := NewResourceFromMap(map[string]Quantity{"first": 7, "second": 10, "third": 6, "forth": 6})
+
+	testCases := []struct {
+		name     string
+		res1     *Resource
+		res2     *Resource
+		prefer   map[string]bool
+		expected *Resource
+	}{
+		{"Both resources nil", nil, nil, prefer, nil},
+		{"First resource nil", nil, smallerRes, prefer, smallerRes},
+		{"Second resource nil", smallerRes, nil, prefer, smallerRes},
+		{"First resource smaller than the second", smallerRes, higherRes, prefer, expected},
+		{"Second resource smaller than the first", higherRes, smallerRes, prefer, expected},
+		{"No preferred types", smallerRes, higherRes, nil, ComponentWiseMin(smallerRes, higherRes)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ComponentWiseMinWeighted(tc.res1, tc.res2, tc.prefer)
+			assert.DeepEqual(t, result, tc.expected)
+		})
+	}
+}
+
 func TestComponentWiseMinOnlyExisting(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -540,6 +1036,31 @@ func TestComponentWiseMinOnlyExisting(t *testing.T) {
 	}
 }
 
+func TestCapTo(t *testing.T) {
+	ceiling := NewResourceFromMap(map[string]Quantity{"first": 5, "second": -5})
+	tests := map[string]struct {
+		res      *Resource
+		ceiling  *Resource
+		expected *Resource
+	}{
+		"nil resource":   {res: nil, ceiling: ceiling, expected: nil},
+		"nil ceiling":    {res: NewResourceFromMap(map[string]Quantity{"first": 10}), ceiling: nil, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"empty resource": {res: NewResource(), ceiling: ceiling, expected: NewResource()},
+		"capped":         {res: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 0}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"first": 5, "second": -5})},
+		"below ceiling":  {res: NewResourceFromMap(map[string]Quantity{"first": 1}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"first": 1})},
+		"not in ceiling": {res: NewResourceFromMap(map[string]Quantity{"first": 10, "third": 100}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"first": 5, "third": 100})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.res.CapTo(tt.ceiling)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+			if tt.res != nil {
+				assert.Assert(t, result != tt.res, "result should be a new resource")
+			}
+		})
+	}
+}
+
 func TestMergeIfNotPresent(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -580,6 +1101,86 @@ func TestMergeIfNotPresent(t *testing.T) {
 	}
 }
 
+func TestOverride(t *testing.T) {
+	testCases := []struct {
+		name      string
+		base      map[string]Quantity
+		overrides map[string]Quantity
+		expected  map[string]Quantity
+	}{
+		{"Override of nil resources should be nil", nil, nil, nil},
+		{"Override of empty resources should be empty resource", map[string]Quantity{}, map[string]Quantity{}, map[string]Quantity{}},
+		{"Override of resource with nil overrides", map[string]Quantity{"first": 5}, nil, map[string]Quantity{"first": 5}},
+		{"Override of nil resource with overrides", nil, map[string]Quantity{"first": 5}, map[string]Quantity{"first": 5}},
+		{"Override replaces value", map[string]Quantity{"first": 5}, map[string]Quantity{"first": 10}, map[string]Quantity{"first": 10}},
+		{"Override replaces with smaller value", map[string]Quantity{"first": 10}, map[string]Quantity{"first": -5}, map[string]Quantity{"first": -5}},
+		{"Override replaces with zero value", map[string]Quantity{"first": 10, "second": 15}, map[string]Quantity{"first": 0}, map[string]Quantity{"first": 0, "second": 15}},
+		{"Override adds extra resource types", map[string]Quantity{"first": 10}, map[string]Quantity{"second": 15}, map[string]Quantity{"first": 10, "second": 15}},
+	}
+	for _, tc := range testCases {
+		var base *Resource
+		var overrides *Resource
+		var expected *Resource
+		if tc.base != nil {
+			base = NewResourceFromMap(tc.base)
+		}
+		if tc.overrides != nil {
+			overrides = NewResourceFromMap(tc.overrides)
+		}
+		if tc.expected != nil {
+			expected = NewResourceFromMap(tc.expected)
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			result := Override(base, overrides)
+			assert.DeepEqual(t, result, expected)
+			if base != nil {
+				assert.DeepEqual(t, base.Resources, tc.base)
+			}
+		})
+	}
+}
+
+func TestMergeMax(t *testing.T) {
+	testCases := []struct {
+		name     string
+		left     map[string]Quantity
+		right    map[string]Quantity
+		expected map[string]Quantity
+	}{
+		{"Max of nil resources should be nil", nil, nil, nil},
+		{"Max of empty resources should be empty resource ", map[string]Quantity{}, map[string]Quantity{}, map[string]Quantity{}},
+		{"Max of positive resource and nil resource", map[string]Quantity{"first": 5}, nil, map[string]Quantity{"first": 5}},
+		{"Max of nil resource and positive resource", nil, map[string]Quantity{"first": 5}, map[string]Quantity{"first": 5}},
+		{"Max of two positive resources", map[string]Quantity{"first": 5}, map[string]Quantity{"first": 10}, map[string]Quantity{"first": 10}},
+		{"Max of two positive resources", map[string]Quantity{"first": 10}, map[string]Quantity{"first": 5}, map[string]Quantity{"first": 10}},
+		{"Max of positive resource and negative resource", map[string]Quantity{"first": -5}, map[string]Quantity{"first": 5}, map[string]Quantity{"first": 5}},
+		{"Max of negative resource and missing type", map[string]Quantity{"first": -5}, map[string]Quantity{"second": 5}, map[string]Quantity{"first": -5, "second": 5}},
+		{"Max of two positive resources with extra resource types", map[string]Quantity{"first": 10}, map[string]Quantity{"first": 5, "second": 15}, map[string]Quantity{"first": 10, "second": 15}},
+		{"Max of two positive resources with extra resource types", map[string]Quantity{"first": 5, "second": 15}, map[string]Quantity{"first": 10}, map[string]Quantity{"first": 10, "second": 15}},
+	}
+	for _, tc := range testCases {
+		var left *Resource
+		var right *Resource
+		var expected *Resource
+		if tc.left != nil {
+			left = NewResourceFromMap(tc.left)
+		}
+		if tc.right != nil {
+			right = NewResourceFromMap(tc.right)
+		}
+		if tc.expected != nil {
+			expected = NewResourceFromMap(tc.expected)
+		}
+		t.Run(tc.name, func(t *testing.T) {
+			result := MergeMax(left, right)
+			assert.DeepEqual(t, result, expected)
+			if result != nil {
+				assert.Assert(t, result != left && result != right, "merge should return a new resource")
+			}
+		})
+	}
+}
+
 func TestComponentWiseMax(t *testing.T) {
 	type inputs struct {
 		res1    map[string]Quantity
@@ -632,29 +1233,194 @@ func TestComponentWiseMax(t *testing.T) {
 	}
 }
 
-func TestToProtoNil(t *testing.T) {
-	// make sure we're nil safe IDE will complain about the non nil check
-	defer func() {
-		if r := recover(); r != nil {
-			t.Fatal("panic on nil resource in proto test")
-		}
-	}()
-	var empty *Resource
-	proto := empty.ToProto()
-	if proto == nil || len(proto.Resources) != 0 {
-		t.Fatalf("to proto on nil resource should have given non nil object")
+func TestComponentWiseAverage(t *testing.T) {
+	tests := map[string]struct {
+		input    []*Resource
+		expected map[string]Quantity
+	}{
+		"nil list":      {input: nil, expected: map[string]Quantity{}},
+		"empty list":    {input: []*Resource{}, expected: map[string]Quantity{}},
+		"only nil":      {input: []*Resource{nil, nil}, expected: map[string]Quantity{}},
+		"single":        {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": 5})}, expected: map[string]Quantity{"first": 5}},
+		"average":       {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": 2}), NewResourceFromMap(map[string]Quantity{"first": 4})}, expected: map[string]Quantity{"first": 3}},
+		"per type":      {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": 2, "second": 4}), NewResourceFromMap(map[string]Quantity{"first": 4}), nil}, expected: map[string]Quantity{"first": 3, "second": 4}},
+		"defined zero":  {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": 0}), NewResourceFromMap(map[string]Quantity{"first": 4})}, expected: map[string]Quantity{"first": 2}},
+		"rounded":       {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": 1, "second": -1}), NewResourceFromMap(map[string]Quantity{"first": 2, "second": -2})}, expected: map[string]Quantity{"first": 1, "second": -1}},
+		"sum overflows": {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64})}, expected: map[string]Quantity{"first": math.MaxInt64 / 2}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, ComponentWiseAverage(tt.input).Resources, tt.expected)
+		})
 	}
 }
 
-func TestToProto(t *testing.T) {
-	var tests = []struct {
-		caseName string
-		input    map[string]Quantity
-		expected int
+func TestBoundingBox(t *testing.T) {
+	tests := map[string]struct {
+		input    []*Resource
+		expected map[string]Quantity
+		count    int
 	}{
-		{"nil", nil, 0},
-		{"empty resource", map[string]Quantity{}, 0},
-		{"setting resource", map[string]Quantity{"first": 5, "second": -5}, 2},
+		"nil list":   {input: nil, expected: map[string]Quantity{}, count: 0},
+		"empty list": {input: []*Resource{}, expected: map[string]Quantity{}, count: 0},
+		"only nil":   {input: []*Resource{nil, nil}, expected: map[string]Quantity{}, count: 0},
+		"single":     {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": 5})}, expected: map[string]Quantity{"first": 5}, count: 1},
+		"per type max": {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": 2, "second": 4}), nil, NewResourceFromMap(map[string]Quantity{"first": 4, "second": 1}), NewResourceFromMap(map[string]Quantity{"third": 1})},
+			expected: map[string]Quantity{"first": 4, "second": 4, "third": 1}, count: 3},
+		"empty request": {input: []*Resource{NewResource(), NewResourceFromMap(map[string]Quantity{"first": 1})}, expected: map[string]Quantity{"first": 1}, count: 2},
+		"negative":      {input: []*Resource{NewResourceFromMap(map[string]Quantity{"first": -2}), NewResourceFromMap(map[string]Quantity{"first": -4})}, expected: map[string]Quantity{"first": -2}, count: 2},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			box, count := BoundingBox(tt.input)
+			assert.DeepEqual(t, box.Resources, tt.expected)
+			assert.Equal(t, count, tt.count)
+		})
+	}
+}
+
+func TestClamp(t *testing.T) {
+	tests := map[string]struct {
+		res, lower, upper *Resource
+		expected          *Resource
+	}{
+		"nil resource": {
+			lower:    NewResourceFromMap(map[string]Quantity{"first": 1}),
+			upper:    NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected: nil,
+		},
+		"nil bounds": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": math.MinInt64, "second": math.MaxInt64}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": math.MinInt64, "second": math.MaxInt64}),
+		},
+		"within bounds": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": 5}),
+			lower:    NewResourceFromMap(map[string]Quantity{"first": 1}),
+			upper:    NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": 5}),
+		},
+		"below and above": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": -5, "second": 50}),
+			lower:    NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}),
+			upper:    NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 10}),
+		},
+		"lower only": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": -5, "second": 50}),
+			lower:    NewResourceFromMap(map[string]Quantity{"first": 0, "second": 0}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": 0, "second": 50}),
+		},
+		"upper only": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": -5, "second": 50}),
+			upper:    NewResourceFromMap(map[string]Quantity{"first": 0, "second": 0}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": -5, "second": 0}),
+		},
+		"partial bounds": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": -5, "second": 50}),
+			lower:    NewResourceFromMap(map[string]Quantity{"first": 0}),
+			upper:    NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": 0, "second": 50}),
+		},
+		"bound types not in resource": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": 5}),
+			lower:    NewResourceFromMap(map[string]Quantity{"second": 1}),
+			upper:    NewResourceFromMap(map[string]Quantity{"third": 10}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": 5}),
+		},
+		"lower above upper": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": 5}),
+			lower:    NewResourceFromMap(map[string]Quantity{"first": 20}),
+			upper:    NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected: NewResourceFromMap(map[string]Quantity{"first": 10}),
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := Clamp(test.res, test.lower, test.upper)
+			if test.expected == nil {
+				assert.Assert(t, result == nil, "expected nil resource got: %v", result)
+				return
+			}
+			assert.Assert(t, DeepEquals(result, test.expected), "unexpected clamped resource: %v", result)
+			assert.Assert(t, result != test.res, "clamp should return a new resource")
+		})
+	}
+}
+
+func TestExceedsInt32(t *testing.T) {
+	tests := map[string]struct {
+		res      *Resource
+		exceeds  []string
+		expected *Resource
+	}{
+		"nil resource":   {res: nil, exceeds: []string{}, expected: nil},
+		"empty resource": {res: NewResource(), exceeds: []string{}, expected: NewResource()},
+		"within range": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": math.MaxInt32, "second": -math.MaxInt32, "third": 0}),
+			exceeds:  []string{},
+			expected: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt32, "second": -math.MaxInt32, "third": 0}),
+		},
+		"exceeds range": {
+			res:      NewResourceFromMap(map[string]Quantity{"second": math.MaxInt32 + 1, "first": math.MinInt32, "third": 1, "fourth": math.MinInt64}),
+			exceeds:  []string{"first", "fourth", "second"},
+			expected: NewResourceFromMap(map[string]Quantity{"second": math.MaxInt32, "first": -math.MaxInt32, "third": 1, "fourth": -math.MaxInt32}),
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, tt.res.ExceedsInt32(), tt.exceeds)
+			clamped := tt.res.ClampToInt32()
+			assert.Assert(t, DeepEquals(clamped, tt.expected), "unexpected clamped resource: got %v, expected %v", clamped, tt.expected)
+			assert.Equal(t, len(clamped.ExceedsInt32()), 0, "clamped resource should not exceed int32")
+		})
+	}
+}
+
+func TestValidateMaxMin(t *testing.T) {
+	res := NewResourceFromMap(map[string]Quantity{"first": 10, "second": -10, "third": 0, "fourth": math.MaxInt64})
+	tests := map[string]struct {
+		res      *Resource
+		limit    Quantity
+		overMax  []string
+		underMin []string
+	}{
+		"nil resource":   {res: nil, limit: 0, overMax: []string{}, underMin: []string{}},
+		"empty resource": {res: NewResource(), limit: 0, overMax: []string{}, underMin: []string{}},
+		"zero limit":     {res: res, limit: 0, overMax: []string{"first", "fourth"}, underMin: []string{"second"}},
+		"limit equal":    {res: res, limit: 10, overMax: []string{"fourth"}, underMin: []string{"second", "third"}},
+		"int32 limit":    {res: res, limit: math.MaxInt32, overMax: []string{"fourth"}, underMin: []string{"first", "second", "third"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, tt.res.ValidateMax(tt.limit), tt.overMax)
+			assert.DeepEqual(t, tt.res.ValidateMin(tt.limit), tt.underMin)
+		})
+	}
+}
+
+func TestToProtoNil(t *testing.T) {
+	// make sure we're nil safe IDE will complain about the non nil check
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatal("panic on nil resource in proto test")
+		}
+	}()
+	var empty *Resource
+	proto := empty.ToProto()
+	if proto == nil || len(proto.Resources) != 0 {
+		t.Fatalf("to proto on nil resource should have given non nil object")
+	}
+}
+
+func TestToProto(t *testing.T) {
+	var tests = []struct {
+		caseName string
+		input    map[string]Quantity
+		expected int
+	}{
+		{"nil", nil, 0},
+		{"empty resource", map[string]Quantity{}, 0},
+		{"setting resource", map[string]Quantity{"first": 5, "second": -5}, 2},
 		{"resource including 0", map[string]Quantity{"first": 5, "second": 0, "third": -5}, 3},
 	}
 	for _, tt := range tests {
@@ -687,6 +1453,34 @@ func TestToProto(t *testing.T) {
 	}
 }
 
+func TestSliceToProto(t *testing.T) {
+	assert.Assert(t, SliceToProto(nil) == nil, "nil slice should return nil")
+	assert.Equal(t, len(SliceToProto([]*Resource{})), 0, "empty slice should return empty slice")
+
+	first := NewResourceFromMap(map[string]Quantity{"first": 5, "second": -5})
+	protos := SliceToProto([]*Resource{first, nil, NewResource()})
+	assert.Equal(t, len(protos), 3, "unexpected number of protos")
+	assert.Equal(t, len(protos[0].Resources), 2, "unexpected number of types in first proto")
+	assert.Equal(t, protos[0].Resources["first"].Value, int64(5), "unexpected value in first proto")
+	assert.Equal(t, protos[0].Resources["second"].Value, int64(-5), "unexpected value in first proto")
+	assert.Assert(t, protos[1] != nil && protos[1].Resources != nil, "nil resource should return an empty proto")
+	assert.Equal(t, len(protos[1].Resources), 0, "nil resource should return an empty proto")
+	assert.Equal(t, len(protos[2].Resources), 0, "empty resource should return an empty proto")
+}
+
+func TestSliceFromProto(t *testing.T) {
+	assert.Assert(t, SliceFromProto(nil) == nil, "nil slice should return nil")
+	assert.Equal(t, len(SliceFromProto([]*si.Resource{})), 0, "empty slice should return empty slice")
+
+	original := []*Resource{NewResourceFromMap(map[string]Quantity{"first": 5, "second": 0}), NewResource()}
+	result := SliceFromProto(append(SliceToProto(original), nil))
+	assert.Equal(t, len(result), 3, "unexpected number of resources")
+	assert.Assert(t, DeepEquals(result[0], original[0]), "round trip changed resource: got %v, expected %v", result[0], original[0])
+	assert.Assert(t, DeepEquals(result[1], original[1]), "round trip changed resource: got %v, expected %v", result[1], original[1])
+	assert.Assert(t, result[2] != nil && result[2].Resources != nil, "nil proto should return an empty resource")
+	assert.Equal(t, len(result[2].Resources), 0, "nil proto should return an empty resource")
+}
+
 func TestNewResourceFromProto(t *testing.T) {
 	var tests = []struct {
 		caseName   string
@@ -721,6 +1515,304 @@ func TestNewResourceFromProto(t *testing.T) {
 	}
 }
 
+func TestNewResourceFromProtoFiltered(t *testing.T) {
+	proto := NewResourceFromMap(map[string]Quantity{"first": 5, "second": 0, "third": -5}).ToProto()
+	tests := map[string]struct {
+		proto    *si.Resource
+		allowed  map[string]bool
+		expected map[string]Quantity
+	}{
+		"nil proto":      {proto: nil, allowed: map[string]bool{"first": true}, expected: map[string]Quantity{}},
+		"nil allowed":    {proto: proto, allowed: nil, expected: map[string]Quantity{"first": 5, "second": 0, "third": -5}},
+		"empty allowed":  {proto: proto, allowed: map[string]bool{}, expected: map[string]Quantity{}},
+		"filtered":       {proto: proto, allowed: map[string]bool{"first": true, "second": true}, expected: map[string]Quantity{"first": 5, "second": 0}},
+		"not allowed":    {proto: proto, allowed: map[string]bool{"first": true, "third": false}, expected: map[string]Quantity{"first": 5}},
+		"allowed unused": {proto: proto, allowed: map[string]bool{"fourth": true}, expected: map[string]Quantity{}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, NewResourceFromProtoFiltered(tt.proto, tt.allowed).Resources, tt.expected)
+		})
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	tests := map[string]struct {
+		res      *Resource
+		expected string
+	}{
+		"nil resource":   {res: nil, expected: "<nil>"},
+		"empty resource": {res: NewResource(), expected: ""},
+		"single type":    {res: NewResourceFromMap(map[string]Quantity{common.Memory: 1024}), expected: "memory=1024"},
+		"sorted types":   {res: NewResourceFromMap(map[string]Quantity{common.CPU: 2000, "pods": 0, common.Memory: 1024, "gpu": -1}), expected: "gpu=-1,memory=1024,pods=0,vcore=2000"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			text, err := tt.res.MarshalText()
+			assert.NilError(t, err, "unexpected marshal error")
+			assert.Equal(t, string(text), tt.expected)
+		})
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	res := NewResourceFromMap(map[string]Quantity{common.CPU: 2000, common.Memory: 1024, "gpu": -1})
+	data, err := json.Marshal(res)
+	assert.NilError(t, err, "marshal of resource failed")
+	assert.Equal(t, string(data), `{"Resources":{"gpu":-1,"memory":1024,"vcore":2000}}`)
+	var out *Resource
+	assert.NilError(t, json.Unmarshal(data, &out), "unmarshal of resource failed")
+	assert.DeepEqual(t, out.Resources, res.Resources)
+
+	// embedded in a struct, including a nil resource
+	type wrapper struct {
+		Used  *Resource
+		Empty *Resource
+	}
+	data, err = json.Marshal(wrapper{Used: res})
+	assert.NilError(t, err, "marshal of wrapper failed")
+	assert.Equal(t, string(data), `{"Used":{"Resources":{"gpu":-1,"memory":1024,"vcore":2000}},"Empty":null}`)
+	var wrapped wrapper
+	assert.NilError(t, json.Unmarshal(data, &wrapped), "unmarshal of wrapper failed")
+	assert.DeepEqual(t, wrapped.Used.Resources, res.Resources)
+	assert.Assert(t, wrapped.Empty == nil, "nil resource should unmarshal to nil")
+}
+
+func TestMarshalYAML(t *testing.T) {
+	var empty *Resource
+	out, err := empty.MarshalYAML()
+	assert.NilError(t, err, "marshal of nil resource failed")
+	assert.DeepEqual(t, out, map[string]interface{}{})
+
+	res := NewResourceFromMap(map[string]Quantity{common.CPU: 1500, common.Memory: 1024})
+	out, err = res.MarshalYAML()
+	assert.NilError(t, err, "marshal of resource failed")
+	assert.DeepEqual(t, out, map[string]interface{}{common.CPU: "1500m", common.Memory: int64(1024)})
+
+	res = NewResourceFromMap(map[string]Quantity{common.Memory: -5})
+	_, err = res.MarshalYAML()
+	assert.ErrorContains(t, err, "negative value", "negative quantity should not be marshalled")
+	_, err = yaml.Marshal(res)
+	assert.ErrorContains(t, err, "negative value", "negative quantity should not be marshalled")
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	tests := map[string]struct {
+		yamlRes  string
+		fail     bool
+		expected *Resource
+	}{
+		"empty mapping": {
+			yamlRes:  "{}",
+			expected: NewResource(),
+		},
+		"plain values": {
+			yamlRes:  "memory: 1024\nvcore: 2\n",
+			expected: NewResourceFromMap(map[string]Quantity{common.Memory: 1024, common.CPU: 2000}),
+		},
+		"unit values": {
+			yamlRes:  "memory: 4Gi\nvcore: 500m\n",
+			expected: NewResourceFromMap(map[string]Quantity{common.Memory: 4 * 1024 * 1024 * 1024, common.CPU: 500}),
+		},
+		"not a mapping": {
+			yamlRes: "- memory\n",
+			fail:    true,
+		},
+		"illegal value": {
+			yamlRes: "memory: xx\n",
+			fail:    true,
+		},
+		"milli not vcore": {
+			yamlRes: "memory: 10m\n",
+			fail:    true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := &Resource{}
+			err := yaml.Unmarshal([]byte(test.yamlRes), res)
+			if test.fail {
+				assert.Assert(t, err != nil, "expected unmarshal error")
+				return
+			}
+			assert.NilError(t, err, "unexpected unmarshal error")
+			assert.Assert(t, DeepEquals(res, test.expected), "unexpected resource: %v", res)
+		})
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	type embedded struct {
+		Limit *Resource `yaml:"limit"`
+	}
+	in := embedded{Limit: NewResourceFromMap(map[string]Quantity{common.CPU: 1500, common.Memory: 4096, "pods": 0})}
+	data, err := yaml.Marshal(in)
+	assert.NilError(t, err, "marshal of embedded resource failed")
+	var out embedded
+	err = yaml.Unmarshal(data, &out)
+	assert.NilError(t, err, "unmarshal of embedded resource failed")
+	assert.Assert(t, DeepEquals(in.Limit, out.Limit), "round trip failed: %s", string(data))
+
+	// a nil resource is written as null and read back as nil
+	data, err = yaml.Marshal(embedded{})
+	assert.NilError(t, err, "marshal of embedded nil resource failed")
+	assert.Equal(t, string(data), "limit: null\n")
+	out = embedded{}
+	err = yaml.Unmarshal(data, &out)
+	assert.NilError(t, err, "unmarshal of embedded nil resource failed")
+	assert.Assert(t, out.Limit == nil, "nil resource should unmarshal to nil")
+}
+
+func TestShardKey(t *testing.T) {
+	res := NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2})
+	assert.Equal(t, res.ShardKey(0), 0, "zero shards should return 0")
+	assert.Equal(t, res.ShardKey(-1), 0, "negative shards should return 0")
+	assert.Equal(t, res.ShardKey(1), 0, "single shard should return 0")
+	var empty *Resource
+	assert.Equal(t, empty.ShardKey(16), NewResource().ShardKey(16), "nil and empty resource should use the same shard")
+
+	// same content, different instances and insertion order
+	same := NewResource()
+	same.Resources["second"] = 2
+	same.Resources["first"] = 1
+	for _, numShards := range []int{2, 7, 16, 1000} {
+		shard := res.ShardKey(numShards)
+		assert.Assert(t, shard >= 0 && shard < numShards, "shard %d out of range for %d shards", shard, numShards)
+		assert.Equal(t, same.ShardKey(numShards), shard, "same resources should use the same shard")
+	}
+
+	// the resources must be spread over the shards
+	shards := make(map[int]bool)
+	for i := 0; i < 100; i++ {
+		shards[NewResourceFromMap(map[string]Quantity{"first": Quantity(i)}).ShardKey(4)] = true
+	}
+	assert.Equal(t, len(shards), 4, "resources not spread over all shards")
+}
+
+func TestGobRoundTrip(t *testing.T) {
+	tests := map[string]*Resource{
+		"empty resource":  NewResource(),
+		"no map":          {},
+		"single value":    NewResourceFromMap(map[string]Quantity{"first": 1}),
+		"multiple values": NewResourceFromMap(map[string]Quantity{"first": 10, "second": -10, "zero": 0}),
+		"limits":          NewResourceFromMap(map[string]Quantity{"max": math.MaxInt64, "min": math.MinInt64}),
+	}
+	for name, res := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := gob.NewEncoder(&buf).Encode(res)
+			assert.NilError(t, err, "gob encode failed")
+			var out *Resource
+			err = gob.NewDecoder(&buf).Decode(&out)
+			assert.NilError(t, err, "gob decode failed")
+			assert.Assert(t, out != nil && out.Resources != nil, "decoded resource must have a map")
+			assert.Assert(t, maps.Equal(out.Resources, res.Resources), "unexpected decoded resource: %v", out)
+		})
+	}
+}
+
+func TestGobEncodeNil(t *testing.T) {
+	var empty *Resource
+	data, err := empty.GobEncode()
+	assert.NilError(t, err, "gob encode of nil resource failed")
+	res := &Resource{}
+	err = res.GobDecode(data)
+	assert.NilError(t, err, "gob decode of nil resource failed")
+	assert.Assert(t, res.Resources != nil && len(res.Resources) == 0, "nil resource should decode as empty resource")
+}
+
+func TestGobDecodeInvalid(t *testing.T) {
+	valid, err := NewResourceFromMap(map[string]Quantity{"first": 10}).GobEncode()
+	assert.NilError(t, err, "gob encode failed")
+	tests := map[string][]byte{
+		"nil data":       nil,
+		"count too high": {0x05, 0x01, 'a', 0x02},
+		"truncated name": valid[:3],
+		"missing value":  valid[:len(valid)-1],
+		"trailing data":  append(append([]byte{}, valid...), 0x01),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := NewResourceFromMap(map[string]Quantity{"existing": 1})
+			err := res.GobDecode(data)
+			assert.Assert(t, err != nil, "expected decode error")
+			assert.Assert(t, maps.Equal(res.Resources, map[string]Quantity{"existing": 1}), "failed decode changed resource")
+		})
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	tests := map[string]*Resource{
+		"empty resource":  NewResource(),
+		"single value":    NewResourceFromMap(map[string]Quantity{"first": 1}),
+		"multiple values": NewResourceFromMap(map[string]Quantity{"first": 10, "second": -10, "zero": 0}),
+		"limits":          NewResourceFromMap(map[string]Quantity{"max": math.MaxInt64, "min": math.MinInt64}),
+	}
+	for name, res := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, err := res.MarshalBinary()
+			assert.NilError(t, err, "binary marshal failed")
+			out := NewResourceFromMap(map[string]Quantity{"existing": 1})
+			err = out.UnmarshalBinary(data)
+			assert.NilError(t, err, "binary unmarshal failed")
+			assert.Assert(t, out.Resources != nil, "unmarshalled resource must have a map")
+			assert.Assert(t, maps.Equal(out.Resources, res.Resources), "unexpected unmarshalled resource: %v", out)
+		})
+	}
+}
+
+func TestBinaryDeterministic(t *testing.T) {
+	left := NewResource()
+	right := NewResource()
+	for i := 0; i < 10; i++ {
+		left.Resources[fmt.Sprintf("type-%d", i)] = Quantity(i)
+		right.Resources[fmt.Sprintf("type-%d", 9-i)] = Quantity(9 - i)
+	}
+	leftData, err := left.MarshalBinary()
+	assert.NilError(t, err, "binary marshal failed")
+	rightData, err := right.MarshalBinary()
+	assert.NilError(t, err, "binary marshal failed")
+	assert.DeepEqual(t, leftData, rightData)
+	// same encoding as used by gob
+	gobData, err := left.GobEncode()
+	assert.NilError(t, err, "gob encode failed")
+	assert.DeepEqual(t, leftData, gobData)
+}
+
+func TestBinaryNil(t *testing.T) {
+	var empty *Resource
+	data, err := empty.MarshalBinary()
+	assert.NilError(t, err, "binary marshal of nil resource failed")
+	assert.Equal(t, len(data), 0, "nil resource should marshal to zero length data")
+	res := NewResourceFromMap(map[string]Quantity{"existing": 1})
+	err = res.UnmarshalBinary(data)
+	assert.NilError(t, err, "binary unmarshal of nil resource failed")
+	assert.Assert(t, res.Resources == nil, "nil resource should unmarshal without a map")
+	// empty resource keeps the map
+	data, err = NewResource().MarshalBinary()
+	assert.NilError(t, err, "binary marshal of empty resource failed")
+	assert.DeepEqual(t, data, []byte{0})
+}
+
+func TestBinaryUnmarshalInvalid(t *testing.T) {
+	valid, err := NewResourceFromMap(map[string]Quantity{"first": 10}).MarshalBinary()
+	assert.NilError(t, err, "binary marshal failed")
+	tests := map[string][]byte{
+		"count too high": {0x05, 0x01, 'a', 0x02},
+		"truncated name": valid[:3],
+		"missing value":  valid[:len(valid)-1],
+		"trailing data":  append(append([]byte{}, valid...), 0x01),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := NewResourceFromMap(map[string]Quantity{"existing": 1})
+			err := res.UnmarshalBinary(data)
+			assert.Assert(t, err != nil, "expected unmarshal error")
+			assert.Assert(t, maps.Equal(res.Resources, map[string]Quantity{"existing": 1}), "failed unmarshal changed resource")
+		})
+	}
+}
+
 func TestMultiplyBy(t *testing.T) {
 	// simple case (nil checks)
 	result := MultiplyBy(nil, 0)
@@ -768,6 +1860,68 @@ func TestMultiplyBy(t *testing.T) {
 	}
 }
 
+func TestMultiplyByCeil(t *testing.T) {
+	// simple case (nil checks)
+	result := MultiplyByCeil(nil, 0)
+	if result == nil || len(result.Resources) != 0 {
+		t.Errorf("nil resource (left) did not return zero resource: %v", result)
+	}
+	// zero multiply factor
+	base := NewResourceFromMap(map[string]Quantity{"first": 5})
+	result = MultiplyByCeil(base, 0)
+	if len(result.Resources) != 0 {
+		t.Errorf("zero factor did not return correct number of resource values: %v", result)
+	}
+
+	tests := map[string]struct {
+		ratio    float64
+		base     map[string]Quantity
+		expected map[string]Quantity
+	}{
+		"whole result": {
+			ratio:    2,
+			base:     map[string]Quantity{"first": 5, "second": -5, "zero": 0},
+			expected: map[string]Quantity{"first": 10, "second": -10, "zero": 0},
+		},
+		"positive factor": {
+			ratio:    1.9,
+			base:     map[string]Quantity{"first": 5, "second": -5},
+			expected: map[string]Quantity{"first": 10, "second": -9},
+		},
+		"negative factor": {
+			ratio:    -1.9,
+			base:     map[string]Quantity{"first": 5, "second": -5},
+			expected: map[string]Quantity{"first": -9, "second": 10},
+		},
+		"small fraction": {
+			ratio:    0.01,
+			base:     map[string]Quantity{"first": 1},
+			expected: map[string]Quantity{"first": 1},
+		},
+		"positive overflow": {
+			ratio:    2,
+			base:     map[string]Quantity{"first": math.MaxInt64},
+			expected: map[string]Quantity{"first": math.MaxInt64},
+		},
+		"positive limit": {
+			ratio:    1,
+			base:     map[string]Quantity{"first": math.MaxInt64},
+			expected: map[string]Quantity{"first": math.MaxInt64},
+		},
+		"negative overflow": {
+			ratio:    -2,
+			base:     map[string]Quantity{"first": math.MaxInt64},
+			expected: map[string]Quantity{"first": math.MinInt64},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := MultiplyByCeil(NewResourceFromMap(test.base), test.ratio)
+			assert.DeepEqual(t, result.Resources, test.expected)
+		})
+	}
+}
+
 func TestMultiply(t *testing.T) {
 	// simple case (nil checks)
 	result := Multiply(nil, 0)
@@ -814,6 +1968,36 @@ func TestMultiply(t *testing.T) {
 	}
 }
 
+func TestMultiplyChecked(t *testing.T) {
+	tests := map[string]struct {
+		base     *Resource
+		ratio    int64
+		expected *Resource
+		err      string
+	}{
+		"nil resource":   {base: nil, ratio: 2, expected: NewResource()},
+		"zero ratio":     {base: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), ratio: 0, expected: NewResourceFromMap(map[string]Quantity{})},
+		"no overflow":    {base: NewResourceFromMap(map[string]Quantity{"first": 5, "second": -5}), ratio: 3, expected: NewResourceFromMap(map[string]Quantity{"first": 15, "second": -15})},
+		"exact maximum":  {base: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), ratio: 1, expected: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64})},
+		"single wrapped": {base: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64/2 + 1, "second": 1}), ratio: 2, expected: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64, "second": 2}), err: "multiplication overflow for resource types: first"},
+		"multiple wrapped": {base: NewResourceFromMap(map[string]Quantity{"second": math.MinInt64, "first": math.MaxInt64, "third": 1}), ratio: -1,
+			expected: NewResourceFromMap(map[string]Quantity{"second": math.MaxInt64, "first": -math.MaxInt64, "third": -1}), err: "multiplication overflow for resource types: second"},
+		"negative wrapped": {base: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64, "second": math.MinInt64 / 2}), ratio: -4,
+			expected: NewResourceFromMap(map[string]Quantity{"first": math.MinInt64, "second": math.MaxInt64}), err: "multiplication overflow for resource types: first, second"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := MultiplyChecked(tt.base, tt.ratio)
+			if tt.err != "" {
+				assert.Error(t, err, tt.err)
+			} else {
+				assert.NilError(t, err, "unexpected overflow error")
+			}
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
 func TestMultiplyToNil(t *testing.T) {
 	// make sure we're nil safe IDE will complain about the non nil check
 	defer func() {
@@ -870,6 +2054,69 @@ func TestMultiplyTo(t *testing.T) {
 	}
 }
 
+func TestNormalizeShape(t *testing.T) {
+	var empty *Resource
+	assert.Assert(t, empty.NormalizeShape() == nil, "nil resource should return nil")
+
+	var tests = []struct {
+		caseName string
+		input    map[string]Quantity
+		output   map[string]Quantity
+	}{
+		{"no types", map[string]Quantity{}, map[string]Quantity{}},
+		{"single type", map[string]Quantity{"first": 1024}, map[string]Quantity{"first": 1}},
+		{"shape", map[string]Quantity{common.CPU: 2000, common.Memory: 4000}, map[string]Quantity{common.CPU: 1, common.Memory: 2}},
+		{"co-prime", map[string]Quantity{"first": 3, "second": 5}, map[string]Quantity{"first": 3, "second": 5}},
+		{"three types", map[string]Quantity{"first": 12, "second": 18, "third": 30}, map[string]Quantity{"first": 2, "second": 3, "third": 5}},
+		{"zero and negative ignored", map[string]Quantity{"first": 4, "second": 8, "zero": 0, "negative": -6}, map[string]Quantity{"first": 1, "second": 2, "zero": 0, "negative": -6}},
+		{"no positive types", map[string]Quantity{"zero": 0, "negative": -6}, map[string]Quantity{"zero": 0, "negative": -6}},
+		{"max value", map[string]Quantity{"first": math.MaxInt64}, map[string]Quantity{"first": 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caseName, func(t *testing.T) {
+			original := NewResourceFromMap(tt.input)
+			inputClone := original.Clone()
+			result := original.NormalizeShape()
+			assert.DeepEqual(t, result.Resources, tt.output)
+			assert.DeepEqual(t, original, inputClone)
+		})
+	}
+	// same shape different scale
+	left := NewResourceFromMap(map[string]Quantity{common.CPU: 2, common.Memory: 4 * 1024})
+	right := NewResourceFromMap(map[string]Quantity{common.CPU: 1, common.Memory: 2 * 1024})
+	assert.Assert(t, DeepEquals(left.NormalizeShape(), right.NormalizeShape()), "same shape should normalize to the same resource")
+}
+
+func TestSameShape(t *testing.T) {
+	tests := map[string]struct {
+		left     *Resource
+		right    *Resource
+		expected bool
+	}{
+		"nil inputs":        {left: nil, right: nil, expected: true},
+		"nil and empty":     {left: nil, right: NewResource(), expected: true},
+		"nil and value":     {left: nil, right: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: false},
+		"same resource":     {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 4}), right: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 4}), expected: true},
+		"integer scale":     {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 4}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2}), expected: true},
+		"fractional scale":  {left: NewResourceFromMap(map[string]Quantity{"first": 3, "second": 6}), right: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 4}), expected: true},
+		"different shape":   {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 4}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 3}), expected: false},
+		"different types":   {left: NewResourceFromMap(map[string]Quantity{"first": 2}), right: NewResourceFromMap(map[string]Quantity{"second": 2}), expected: false},
+		"extra type":        {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 0}), right: NewResourceFromMap(map[string]Quantity{"first": 2}), expected: false},
+		"matching zeros":    {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 0}), right: NewResourceFromMap(map[string]Quantity{"first": 4, "second": 0}), expected: true},
+		"zero not matched":  {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": 0}), right: NewResourceFromMap(map[string]Quantity{"first": 4, "second": 1}), expected: false},
+		"negative scale":    {left: NewResourceFromMap(map[string]Quantity{"first": -2, "second": -4}), right: NewResourceFromMap(map[string]Quantity{"first": -1, "second": -2}), expected: true},
+		"sign mismatch":     {left: NewResourceFromMap(map[string]Quantity{"first": -2, "second": -4}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2}), expected: false},
+		"mixed signs":       {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": -4}), right: NewResourceFromMap(map[string]Quantity{"first": 1, "second": -2}), expected: true},
+		"mixed signs scale": {left: NewResourceFromMap(map[string]Quantity{"first": 2, "second": -1}), right: NewResourceFromMap(map[string]Quantity{"first": 4, "second": -1}), expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, SameShape(tt.left, tt.right), tt.expected, "unexpected shape comparison")
+			assert.Equal(t, SameShape(tt.right, tt.left), tt.expected, "shape comparison not symmetric")
+		})
+	}
+}
+
 func TestWrapSafe(t *testing.T) {
 	// additions and subtract use the same code
 	if addVal(math.MaxInt64, 1) != math.MaxInt64 {
@@ -929,6 +2176,45 @@ func TestWrapSafe(t *testing.T) {
 	}
 }
 
+func TestOverflowHook(t *testing.T) {
+	type event struct {
+		op         string
+		valA, valB Quantity
+		ratio      float64
+	}
+	var events []event
+	SetOverflowHook(func(op string, valA, valB Quantity, ratio float64) {
+		events = append(events, event{op, valA, valB, ratio})
+	})
+	defer SetOverflowHook(nil)
+
+	// no wrapping no events
+	addVal(1, 1)
+	mulVal(2, 2)
+	mulValRatio(2, 1.5)
+	mulValRatioCeil(2, 1.5)
+	assert.Equal(t, len(events), 0, "unexpected overflow events")
+
+	addVal(math.MaxInt64, 1)
+	subVal(math.MinInt64, 1)
+	mulVal(math.MaxInt64, -2)
+	mulValRatio(math.MaxInt64, 2)
+	mulValRatioCeil(math.MinInt64, 2.5)
+	expected := []event{
+		{"add", math.MaxInt64, 1, 0},
+		{"add", math.MinInt64, -1, 0},
+		{"multiply", math.MaxInt64, -2, 0},
+		{"multiplyRatio", math.MaxInt64, 0, 2},
+		{"multiplyRatioCeil", math.MinInt64, 0, 2.5},
+	}
+	assert.Assert(t, reflect.DeepEqual(events, expected), "unexpected overflow events: %v", events)
+
+	// removing the hook stops the events
+	SetOverflowHook(nil)
+	addVal(math.MaxInt64, 1)
+	assert.Equal(t, len(events), len(expected), "events generated after removing the hook")
+}
+
 func TestAdd(t *testing.T) {
 	// simple case (nil checks)
 	result := Add(nil, nil)
@@ -960,39 +2246,123 @@ func TestAdd(t *testing.T) {
 	}
 }
 
-func TestAddToNil(t *testing.T) {
-	// make sure we're nil safe IDE will complain about the non nil check
-	defer func() {
-		if r := recover(); r != nil {
-			t.Fatal("panic on nil resource in addto test")
-		}
-	}()
-	var empty *Resource
-	empty.AddTo(NewResource())
-}
-
-func TestAddTo(t *testing.T) {
-	// simple case (nil checks) & empty resources
-	base := NewResource()
-	base.AddTo(nil)
-	if len(base.Resources) != 0 {
-		t.Errorf("addto nil resource modified base resource: %v", base)
+func TestAddMany(t *testing.T) {
+	// simple case (nil checks)
+	result := AddMany()
+	if result == nil || len(result.Resources) != 0 {
+		t.Errorf("add many without resources did not return zero resource: %v", result)
+	}
+	result = AddMany(nil, nil)
+	if result == nil || len(result.Resources) != 0 {
+		t.Errorf("add many nil resources did not return zero resource: %v", result)
 	}
-
-	// simple resources
 	res1 := NewResourceFromMap(map[string]Quantity{"a": 5})
-	base.AddTo(res1)
-	if len(base.Resources) != 1 && base.Resources["a"] != 5 {
-		t.Errorf("addto simple resource did not return merged input resource: %v", base)
+	result = AddMany(res1)
+	if result == nil || result == res1 || !reflect.DeepEqual(result.Resources, res1.Resources) {
+		t.Errorf("add many single resource did not return cloned input resource: %v", result)
 	}
 
-	// complex case: just checking the resource merge, values check is secondary
-	base = &Resource{Resources: map[string]Quantity{"a": 0, "b": 1}}
-	res1 = &Resource{Resources: map[string]Quantity{"a": 1, "c": 0, "d": -1}}
-	base.AddTo(res1)
-
-	expected := map[string]Quantity{"a": 1, "b": 1, "c": 0, "d": -1}
-	if !reflect.DeepEqual(base.Resources, expected) {
+	// complex case: merge and values
+	res1 = &Resource{Resources: map[string]Quantity{"a": 0, "b": 1}}
+	res2 := &Resource{Resources: map[string]Quantity{"a": 1, "c": 0, "d": -1}}
+	res3 := &Resource{Resources: map[string]Quantity{"a": 2, "d": math.MinInt64}}
+	result = AddMany(res1, nil, res2, res3)
+	expected := map[string]Quantity{"a": 3, "b": 1, "c": 0, "d": math.MinInt64}
+	if !reflect.DeepEqual(result.Resources, expected) {
+		t.Errorf("add many failed expected %v, actual %v", expected, result.Resources)
+	}
+	// inputs unchanged
+	assert.Assert(t, reflect.DeepEqual(res1.Resources, map[string]Quantity{"a": 0, "b": 1}), "input resource changed")
+}
+
+func TestAddCapped(t *testing.T) {
+	ceiling := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 0})
+	tests := map[string]struct {
+		base     *Resource
+		add      *Resource
+		ceiling  *Resource
+		expected *Resource
+	}{
+		"nil inputs":      {base: nil, add: nil, ceiling: ceiling, expected: NewResource()},
+		"nil ceiling":     {base: NewResourceFromMap(map[string]Quantity{"first": 8}), add: NewResourceFromMap(map[string]Quantity{"first": 8}), ceiling: nil, expected: NewResourceFromMap(map[string]Quantity{"first": 16})},
+		"below ceiling":   {base: NewResourceFromMap(map[string]Quantity{"first": 4}), add: NewResourceFromMap(map[string]Quantity{"first": 5}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"first": 9})},
+		"capped":          {base: NewResourceFromMap(map[string]Quantity{"first": 8}), add: NewResourceFromMap(map[string]Quantity{"first": 8}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"union capped":    {base: NewResourceFromMap(map[string]Quantity{"first": 1}), add: NewResourceFromMap(map[string]Quantity{"second": 5}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 0})},
+		"not in ceiling":  {base: NewResourceFromMap(map[string]Quantity{"third": 1}), add: NewResourceFromMap(map[string]Quantity{"third": 100}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"third": 101})},
+		"ceiling ignored": {base: NewResourceFromMap(map[string]Quantity{"third": 1}), add: nil, ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"third": 1})},
+		"overflow capped": {base: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), add: NewResourceFromMap(map[string]Quantity{"first": 1}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"overflow":        {base: NewResourceFromMap(map[string]Quantity{"third": math.MaxInt64}), add: NewResourceFromMap(map[string]Quantity{"third": 1}), ceiling: ceiling, expected: NewResourceFromMap(map[string]Quantity{"third": math.MaxInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := AddCapped(tt.base, tt.add, tt.ceiling)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestSubMany(t *testing.T) {
+	// simple case (nil checks)
+	result := SubMany(nil)
+	if result == nil || len(result.Resources) != 0 {
+		t.Errorf("sub many nil resource did not return zero resource: %v", result)
+	}
+	res1 := NewResourceFromMap(map[string]Quantity{"a": 5})
+	result = SubMany(res1, nil)
+	if result == nil || result == res1 || !reflect.DeepEqual(result.Resources, res1.Resources) {
+		t.Errorf("sub many nil resource did not return cloned base resource: %v", result)
+	}
+	result = SubMany(nil, res1)
+	if result == nil || !reflect.DeepEqual(result.Resources, map[string]Quantity{"a": -5}) {
+		t.Errorf("sub many from nil base did not return negative resource: %v", result)
+	}
+
+	// complex case: merge and values
+	res1 = &Resource{Resources: map[string]Quantity{"a": 5, "b": 1}}
+	res2 := &Resource{Resources: map[string]Quantity{"a": 1, "c": 0, "d": -1}}
+	res3 := &Resource{Resources: map[string]Quantity{"a": 2, "d": 3}}
+	result = SubMany(res1, res2, nil, res3)
+	expected := map[string]Quantity{"a": 2, "b": 1, "c": 0, "d": -2}
+	if !reflect.DeepEqual(result.Resources, expected) {
+		t.Errorf("sub many failed expected %v, actual %v", expected, result.Resources)
+	}
+	// inputs unchanged
+	assert.Assert(t, reflect.DeepEqual(res1.Resources, map[string]Quantity{"a": 5, "b": 1}), "base resource changed")
+}
+
+func TestAddToNil(t *testing.T) {
+	// make sure we're nil safe IDE will complain about the non nil check
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatal("panic on nil resource in addto test")
+		}
+	}()
+	var empty *Resource
+	empty.AddTo(NewResource())
+}
+
+func TestAddTo(t *testing.T) {
+	// simple case (nil checks) & empty resources
+	base := NewResource()
+	base.AddTo(nil)
+	if len(base.Resources) != 0 {
+		t.Errorf("addto nil resource modified base resource: %v", base)
+	}
+
+	// simple resources
+	res1 := NewResourceFromMap(map[string]Quantity{"a": 5})
+	base.AddTo(res1)
+	if len(base.Resources) != 1 && base.Resources["a"] != 5 {
+		t.Errorf("addto simple resource did not return merged input resource: %v", base)
+	}
+
+	// complex case: just checking the resource merge, values check is secondary
+	base = &Resource{Resources: map[string]Quantity{"a": 0, "b": 1}}
+	res1 = &Resource{Resources: map[string]Quantity{"a": 1, "c": 0, "d": -1}}
+	base.AddTo(res1)
+
+	expected := map[string]Quantity{"a": 1, "b": 1, "c": 0, "d": -1}
+	if !reflect.DeepEqual(base.Resources, expected) {
 		t.Errorf("addto failed expected %v, actual %v", expected, base.Resources)
 	}
 }
@@ -1028,6 +2398,52 @@ func TestSub(t *testing.T) {
 	}
 }
 
+func TestApplyDelta(t *testing.T) {
+	var empty *Resource
+	assert.Assert(t, !empty.ApplyDelta(NewResourceFromMap(map[string]Quantity{"first": 1})), "nil base should not change")
+
+	tests := map[string]struct {
+		base     map[string]Quantity
+		delta    *Resource
+		changed  bool
+		expected map[string]Quantity
+	}{
+		"nil delta":      {base: map[string]Quantity{"first": 1}, delta: nil, changed: false, expected: map[string]Quantity{"first": 1}},
+		"empty delta":    {base: map[string]Quantity{"first": 1}, delta: NewResource(), changed: false, expected: map[string]Quantity{"first": 1}},
+		"zero delta":     {base: map[string]Quantity{"first": 1}, delta: NewResourceFromMap(map[string]Quantity{"first": 0, "second": 0}), changed: false, expected: map[string]Quantity{"first": 1, "second": 0}},
+		"positive delta": {base: map[string]Quantity{"first": 1}, delta: NewResourceFromMap(map[string]Quantity{"first": 2}), changed: true, expected: map[string]Quantity{"first": 3}},
+		"negative delta": {base: map[string]Quantity{"first": 1}, delta: NewResourceFromMap(map[string]Quantity{"first": 0, "second": -2}), changed: true, expected: map[string]Quantity{"first": 1, "second": -2}},
+		"clamped":        {base: map[string]Quantity{"first": math.MaxInt64}, delta: NewResourceFromMap(map[string]Quantity{"first": 1}), changed: false, expected: map[string]Quantity{"first": math.MaxInt64}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			base := NewResourceFromMap(tt.base)
+			assert.Equal(t, base.ApplyDelta(tt.delta), tt.changed, "unexpected changed result")
+			assert.DeepEqual(t, base.Resources, tt.expected)
+		})
+	}
+}
+
+func TestDrainInto(t *testing.T) {
+	var empty *Resource
+	dst := NewResourceFromMap(map[string]Quantity{"first": 1})
+	empty.DrainInto(dst)
+	assert.DeepEqual(t, dst.Resources, map[string]Quantity{"first": 1})
+
+	src := NewResourceFromMap(map[string]Quantity{"first": 1})
+	src.DrainInto(nil)
+	assert.DeepEqual(t, src.Resources, map[string]Quantity{"first": 1})
+	src.DrainInto(src)
+	assert.DeepEqual(t, src.Resources, map[string]Quantity{"first": 1})
+
+	src = NewResourceFromMap(map[string]Quantity{"first": 2, "second": 0, "third": math.MaxInt64})
+	dst = NewResourceFromMap(map[string]Quantity{"first": 1, "third": 1, "fourth": 4})
+	src.DrainInto(dst)
+	assert.DeepEqual(t, dst.Resources, map[string]Quantity{"first": 3, "second": 0, "third": math.MaxInt64, "fourth": 4})
+	assert.Assert(t, src.Resources != nil, "drained resource map should not be nil")
+	assert.Equal(t, len(src.Resources), 0, "drained resource should be empty")
+}
+
 func TestSubFromNil(t *testing.T) {
 	// make sure we're nil safe IDE will complain about the non nil check
 	defer func() {
@@ -1142,6 +2558,79 @@ func TestAddOnlyExisting(t *testing.T) {
 	}
 }
 
+func TestSubWithFloor(t *testing.T) {
+	floor := NewResourceFromMap(map[string]Quantity{"first": 5, "second": -5})
+	tests := map[string]struct {
+		left     *Resource
+		right    *Resource
+		floor    *Resource
+		expected *Resource
+	}{
+		"nil left":         {left: nil, right: NewResourceFromMap(map[string]Quantity{"first": 1}), floor: floor, expected: NewResource()},
+		"nil right":        {left: NewResourceFromMap(map[string]Quantity{"first": 10}), right: nil, floor: floor, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"nil right floor":  {left: NewResourceFromMap(map[string]Quantity{"first": 1}), right: nil, floor: floor, expected: NewResourceFromMap(map[string]Quantity{"first": 5})},
+		"above floor":      {left: NewResourceFromMap(map[string]Quantity{"first": 10}), right: NewResourceFromMap(map[string]Quantity{"first": 4}), floor: floor, expected: NewResourceFromMap(map[string]Quantity{"first": 6})},
+		"below floor":      {left: NewResourceFromMap(map[string]Quantity{"first": 10}), right: NewResourceFromMap(map[string]Quantity{"first": 8}), floor: floor, expected: NewResourceFromMap(map[string]Quantity{"first": 5})},
+		"negative floor":   {left: NewResourceFromMap(map[string]Quantity{"second": 1}), right: NewResourceFromMap(map[string]Quantity{"second": 4}), floor: floor, expected: NewResourceFromMap(map[string]Quantity{"second": -3})},
+		"negative floored": {left: NewResourceFromMap(map[string]Quantity{"second": 1}), right: NewResourceFromMap(map[string]Quantity{"second": 10}), floor: floor, expected: NewResourceFromMap(map[string]Quantity{"second": -5})},
+		"type not floored": {left: NewResourceFromMap(map[string]Quantity{"third": 1}), right: NewResourceFromMap(map[string]Quantity{"third": 10}), floor: floor, expected: NewResourceFromMap(map[string]Quantity{"third": 0})},
+		"only right type":  {left: NewResourceFromMap(map[string]Quantity{"first": 10}), right: NewResourceFromMap(map[string]Quantity{"third": 10}), floor: floor, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"nil floor":        {left: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 10}), right: NewResourceFromMap(map[string]Quantity{"first": 5, "second": 5, "third": 5}), floor: nil, expected: NewResourceFromMap(map[string]Quantity{"first": 0, "second": 5})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := SubWithFloor(tt.left, tt.right, tt.floor)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestFree(t *testing.T) {
+	tests := map[string]struct {
+		capacity *Resource
+		used     *Resource
+		expected *Resource
+	}{
+		"nil capacity":    {capacity: nil, used: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: NewResource()},
+		"nil used":        {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: nil, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"negative":        {capacity: NewResourceFromMap(map[string]Quantity{"first": -1}), used: nil, expected: NewResourceFromMap(map[string]Quantity{"first": 0})},
+		"partly used":     {capacity: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), used: NewResourceFromMap(map[string]Quantity{"first": 4}), expected: NewResourceFromMap(map[string]Quantity{"first": 6, "second": 5})},
+		"over used":       {capacity: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), used: NewResourceFromMap(map[string]Quantity{"first": 4, "second": 8}), expected: NewResourceFromMap(map[string]Quantity{"first": 6, "second": 0})},
+		"only used type":  {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: NewResourceFromMap(map[string]Quantity{"first": 10, "third": 5}), expected: NewResourceFromMap(map[string]Quantity{"first": 0})},
+		"negative usage":  {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: NewResourceFromMap(map[string]Quantity{"first": -5}), expected: NewResourceFromMap(map[string]Quantity{"first": 15})},
+		"overflow capped": {capacity: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), used: NewResourceFromMap(map[string]Quantity{"first": -5}), expected: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.capacity.Free(tt.used)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
+func TestExcess(t *testing.T) {
+	tests := map[string]struct {
+		usage    *Resource
+		limit    *Resource
+		expected *Resource
+	}{
+		"nil usage":      {usage: nil, limit: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: NewResource()},
+		"nil limit":      {usage: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 0, "third": -1}), limit: nil, expected: NewResourceFromMap(map[string]Quantity{"first": 10})},
+		"within limit":   {usage: NewResourceFromMap(map[string]Quantity{"first": 10}), limit: NewResourceFromMap(map[string]Quantity{"first": 10}), expected: NewResource()},
+		"over limit":     {usage: NewResourceFromMap(map[string]Quantity{"first": 12, "second": 3}), limit: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), expected: NewResourceFromMap(map[string]Quantity{"first": 2})},
+		"undefined type": {usage: NewResourceFromMap(map[string]Quantity{"first": 1, "third": 5}), limit: NewResourceFromMap(map[string]Quantity{"first": 10}), expected: NewResourceFromMap(map[string]Quantity{"third": 5})},
+		"only limit":     {usage: NewResourceFromMap(map[string]Quantity{"first": 1}), limit: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), expected: NewResource()},
+		"negative limit": {usage: NewResourceFromMap(map[string]Quantity{"first": 0}), limit: NewResourceFromMap(map[string]Quantity{"first": -5}), expected: NewResourceFromMap(map[string]Quantity{"first": 5})},
+		"overflow":       {usage: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), limit: NewResourceFromMap(map[string]Quantity{"first": -5}), expected: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64})},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.usage.Excess(tt.limit)
+			assert.Assert(t, DeepEquals(result, tt.expected), "unexpected result: got %v, expected %v", result, tt.expected)
+		})
+	}
+}
+
 func TestSubErrorNegative(t *testing.T) {
 	// simple case (nil checks)
 	result, err := SubErrorNegative(nil, nil)
@@ -1176,6 +2665,84 @@ func TestSubErrorNegative(t *testing.T) {
 	}
 }
 
+func TestDiff(t *testing.T) {
+	tests := map[string]struct {
+		oldRes, newRes          *Resource
+		added, removed, changed map[string]Quantity
+	}{
+		"both nil": {
+			added:   map[string]Quantity{},
+			removed: map[string]Quantity{},
+			changed: map[string]Quantity{},
+		},
+		"nil old": {
+			newRes:  NewResourceFromMap(map[string]Quantity{"first": 1, "zero": 0}),
+			added:   map[string]Quantity{"first": 1, "zero": 0},
+			removed: map[string]Quantity{},
+			changed: map[string]Quantity{},
+		},
+		"nil new": {
+			oldRes:  NewResourceFromMap(map[string]Quantity{"first": 1, "zero": 0}),
+			added:   map[string]Quantity{},
+			removed: map[string]Quantity{"first": 1, "zero": 0},
+			changed: map[string]Quantity{},
+		},
+		"equal": {
+			oldRes:  NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2}),
+			newRes:  NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2}),
+			added:   map[string]Quantity{},
+			removed: map[string]Quantity{},
+			changed: map[string]Quantity{},
+		},
+		"all changes": {
+			oldRes:  NewResourceFromMap(map[string]Quantity{"same": 1, "up": 2, "down": 10, "gone": 5}),
+			newRes:  NewResourceFromMap(map[string]Quantity{"same": 1, "up": 5, "down": 4, "new": 3}),
+			added:   map[string]Quantity{"new": 3},
+			removed: map[string]Quantity{"gone": 5},
+			changed: map[string]Quantity{"up": 3, "down": -6},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			oldClone := test.oldRes.Clone()
+			newClone := test.newRes.Clone()
+			added, removed, changed := Diff(test.oldRes, test.newRes)
+			assert.DeepEqual(t, added.Resources, test.added)
+			assert.DeepEqual(t, removed.Resources, test.removed)
+			assert.DeepEqual(t, changed.Resources, test.changed)
+			assert.DeepEqual(t, test.oldRes, oldClone)
+			assert.DeepEqual(t, test.newRes, newClone)
+		})
+	}
+}
+
+func TestChangeEvent(t *testing.T) {
+	tests := map[string]struct {
+		oldRes, newRes *Resource
+		expected       map[string]int64
+		changed        bool
+	}{
+		"both nil":  {expected: map[string]int64{}, changed: false},
+		"unchanged": {oldRes: NewResourceFromMap(map[string]Quantity{"first": 1}), newRes: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: map[string]int64{}, changed: false},
+		"added":     {oldRes: nil, newRes: NewResourceFromMap(map[string]Quantity{"first": 5}), expected: map[string]int64{"first": 5}, changed: true},
+		"removed":   {oldRes: NewResourceFromMap(map[string]Quantity{"first": 5}), newRes: nil, expected: map[string]int64{"first": -5}, changed: true},
+		"zero only": {oldRes: NewResourceFromMap(map[string]Quantity{"first": 0}), newRes: NewResourceFromMap(map[string]Quantity{"second": 0}), expected: map[string]int64{}, changed: false},
+		"mixed": {
+			oldRes:   NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5, "third": 3}),
+			newRes:   NewResourceFromMap(map[string]Quantity{"first": 4, "second": 5, "fourth": 2}),
+			expected: map[string]int64{"first": -6, "third": -3, "fourth": 2},
+			changed:  true,
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			deltas, changed := ChangeEvent(tt.oldRes, tt.newRes)
+			assert.DeepEqual(t, deltas, tt.expected)
+			assert.Equal(t, changed, tt.changed)
+		})
+	}
+}
+
 func TestEqualsOrEmpty(t *testing.T) {
 	var tests = []struct {
 		left, right *Resource
@@ -1222,6 +2789,84 @@ func TestDeepEquals(t *testing.T) {
 	}
 }
 
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name        string
+		left, right *Resource
+		expected    int
+	}{
+		{"both nil", nil, nil, 0},
+		{"nil left", nil, NewResource(), -1},
+		{"nil right", NewResource(), nil, 1},
+		{"both empty", NewResource(), NewResource(), 0},
+		{"empty left", NewResource(), NewResourceFromMap(map[string]Quantity{"first": 0}), -1},
+		{"equal", NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2}), NewResourceFromMap(map[string]Quantity{"second": 2, "first": 1}), 0},
+		{"smaller value", NewResourceFromMap(map[string]Quantity{"first": 1}), NewResourceFromMap(map[string]Quantity{"first": 2}), -1},
+		{"larger value", NewResourceFromMap(map[string]Quantity{"first": 2}), NewResourceFromMap(map[string]Quantity{"first": -2}), 1},
+		{"type before", NewResourceFromMap(map[string]Quantity{"first": 10}), NewResourceFromMap(map[string]Quantity{"second": 1}), -1},
+		{"type after", NewResourceFromMap(map[string]Quantity{"second": 1}), NewResourceFromMap(map[string]Quantity{"first": 10}), 1},
+		{"prefix left", NewResourceFromMap(map[string]Quantity{"first": 1}), NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}), -1},
+		{"prefix right", NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}), NewResourceFromMap(map[string]Quantity{"first": 1}), 1},
+		{"zero value not ignored", NewResourceFromMap(map[string]Quantity{"first": 1, "zero": 0}), NewResourceFromMap(map[string]Quantity{"first": 1}), 1},
+		{"types before values", NewResourceFromMap(map[string]Quantity{"first": 2, "second": 1}), NewResourceFromMap(map[string]Quantity{"first": 1, "third": 1}), -1},
+		{"prefix before values", NewResourceFromMap(map[string]Quantity{"first": 2}), NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}), -1},
+		{"later value", NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}), NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2}), -1},
+		{"same object", Zero, Zero, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Compare(tt.left, tt.right), tt.expected, "unexpected compare result")
+			assert.Equal(t, Compare(tt.right, tt.left), -tt.expected, "compare result not symmetric")
+		})
+	}
+}
+
+func TestRelationPerType(t *testing.T) {
+	tests := map[string]struct {
+		left     *Resource
+		right    *Resource
+		expected map[string]int
+	}{
+		"nil inputs":  {left: nil, right: nil, expected: map[string]int{}},
+		"nil left":    {left: nil, right: NewResourceFromMap(map[string]Quantity{"first": 1, "second": -1, "third": 0}), expected: map[string]int{"first": -1, "second": 1, "third": 0}},
+		"nil right":   {left: NewResourceFromMap(map[string]Quantity{"first": 1, "second": -1, "third": 0}), right: nil, expected: map[string]int{"first": 1, "second": -1, "third": 0}},
+		"same values": {left: NewResourceFromMap(map[string]Quantity{"first": 5}), right: NewResourceFromMap(map[string]Quantity{"first": 5}), expected: map[string]int{"first": 0}},
+		"mixed": {left: NewResourceFromMap(map[string]Quantity{"first": 5, "second": 1, "third": 2}), right: NewResourceFromMap(map[string]Quantity{"first": 5, "second": 2, "fourth": 1}),
+			expected: map[string]int{"first": 0, "second": -1, "third": 1, "fourth": -1}},
+		"extremes": {left: NewResourceFromMap(map[string]Quantity{"first": math.MinInt64}), right: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}), expected: map[string]int{"first": -1}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, RelationPerType(tt.left, tt.right), tt.expected)
+		})
+	}
+}
+
+func TestCompareSort(t *testing.T) {
+	list := []*Resource{
+		NewResourceFromMap(map[string]Quantity{"second": 1}),
+		NewResourceFromMap(map[string]Quantity{"first": 2}),
+		nil,
+		NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}),
+		NewResource(),
+		NewResourceFromMap(map[string]Quantity{"first": 1}),
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return Compare(list[i], list[j]) < 0
+	})
+	expected := []*Resource{
+		nil,
+		NewResource(),
+		NewResourceFromMap(map[string]Quantity{"first": 1}),
+		NewResourceFromMap(map[string]Quantity{"first": 2}),
+		NewResourceFromMap(map[string]Quantity{"first": 1, "second": 1}),
+		NewResourceFromMap(map[string]Quantity{"second": 1}),
+	}
+	for i := range expected {
+		assert.Equal(t, Compare(list[i], expected[i]), 0, "unexpected sort order at index %d: %v", i, list[i])
+	}
+}
+
 func TestFitIn(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1246,6 +2891,195 @@ func TestFitIn(t *testing.T) {
 	}
 }
 
+func TestFitInWithBuffer(t *testing.T) {
+	capacity := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 100})
+	tests := map[string]struct {
+		capacity *Resource
+		request  *Resource
+		buffer   *Resource
+		expected bool
+	}{
+		"nil request":       {capacity: capacity, request: nil, buffer: NewResourceFromMap(map[string]Quantity{"first": 20}), expected: true},
+		"nil capacity":      {capacity: nil, request: NewResourceFromMap(map[string]Quantity{"first": 1}), buffer: nil, expected: false},
+		"nil buffer fits":   {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 10}), buffer: nil, expected: true},
+		"nil buffer no fit": {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 11}), buffer: nil, expected: false},
+		"fits in buffer":    {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 9, "second": 90}), buffer: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 10}), expected: true},
+		"fits in capacity":  {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 10}), buffer: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: false},
+		"other buffer type": {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 10}), buffer: NewResourceFromMap(map[string]Quantity{"second": 50, "third": 5}), expected: true},
+		"buffer too large":  {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 0}), buffer: NewResourceFromMap(map[string]Quantity{"first": 20}), expected: true},
+		"buffer floored":    {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 1}), buffer: NewResourceFromMap(map[string]Quantity{"first": 20}), expected: false},
+		"negative buffer":   {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"first": 15}), buffer: NewResourceFromMap(map[string]Quantity{"first": -5}), expected: true},
+		"undefined type":    {capacity: capacity, request: NewResourceFromMap(map[string]Quantity{"third": 1}), buffer: NewResource(), expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.capacity.FitInWithBuffer(tt.request, tt.buffer), tt.expected, "unexpected fit result")
+		})
+	}
+}
+
+func TestFitInIgnoringMissing(t *testing.T) {
+	node := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5})
+	ignore := []string{"optional"}
+	tests := map[string]struct {
+		larger   *Resource
+		request  *Resource
+		ignore   []string
+		expected bool
+	}{
+		"nil request":        {larger: node, request: nil, ignore: ignore, expected: true},
+		"nil larger":         {larger: nil, request: NewResourceFromMap(map[string]Quantity{"first": 1}), ignore: ignore, expected: false},
+		"nil larger ignored": {larger: nil, request: NewResourceFromMap(map[string]Quantity{"optional": 1}), ignore: ignore, expected: true},
+		"nil ignore":         {larger: node, request: NewResourceFromMap(map[string]Quantity{"first": 10, "optional": 1}), ignore: nil, expected: false},
+		"fits":               {larger: node, request: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), ignore: ignore, expected: true},
+		"no fit":             {larger: node, request: NewResourceFromMap(map[string]Quantity{"first": 11}), ignore: ignore, expected: false},
+		"missing ignored":    {larger: node, request: NewResourceFromMap(map[string]Quantity{"first": 1, "optional": 100}), ignore: ignore, expected: true},
+		"missing mandatory":  {larger: node, request: NewResourceFromMap(map[string]Quantity{"first": 1, "third": 1}), ignore: ignore, expected: false},
+		"defined ignored":    {larger: NewResourceFromMap(map[string]Quantity{"optional": 1}), request: NewResourceFromMap(map[string]Quantity{"optional": 100}), ignore: ignore, expected: true},
+		"ignore mandatory":   {larger: node, request: NewResourceFromMap(map[string]Quantity{"first": 100, "second": 1}), ignore: []string{"first"}, expected: true},
+		"negative larger":    {larger: NewResourceFromMap(map[string]Quantity{"first": -10}), request: NewResourceFromMap(map[string]Quantity{"first": 0, "second": -10}), ignore: ignore, expected: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.larger.FitInIgnoringMissing(tt.request, tt.ignore), tt.expected, "unexpected fit result")
+			if len(tt.ignore) == 0 {
+				assert.Equal(t, tt.larger.FitIn(tt.request), tt.expected, "FitInIgnoringMissing and FitIn disagree")
+			}
+		})
+	}
+}
+
+func TestLargestDeficit(t *testing.T) {
+	available := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5, "negative": -5})
+	tests := map[string]struct {
+		available *Resource
+		request   *Resource
+		name      string
+		deficit   Quantity
+	}{
+		"nil request":        {available: available, request: nil, name: "", deficit: 0},
+		"nil available":      {available: nil, request: NewResourceFromMap(map[string]Quantity{"first": 1}), name: "first", deficit: 1},
+		"fits":               {available: available, request: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), name: "", deficit: 0},
+		"single deficit":     {available: available, request: NewResourceFromMap(map[string]Quantity{"first": 12, "second": 5}), name: "first", deficit: 2},
+		"largest deficit":    {available: available, request: NewResourceFromMap(map[string]Quantity{"first": 12, "second": 8}), name: "second", deficit: 3},
+		"tie":                {available: available, request: NewResourceFromMap(map[string]Quantity{"first": 13, "second": 8}), name: "first", deficit: 3},
+		"undefined type":     {available: available, request: NewResourceFromMap(map[string]Quantity{"first": 11, "third": 4}), name: "third", deficit: 4},
+		"negative available": {available: available, request: NewResourceFromMap(map[string]Quantity{"negative": 1}), name: "negative", deficit: 1},
+		"negative request":   {available: available, request: NewResourceFromMap(map[string]Quantity{"third": -1}), name: "", deficit: 0},
+		"no overflow":        {available: available, request: NewResourceFromMap(map[string]Quantity{"third": math.MaxInt64}), name: "third", deficit: math.MaxInt64},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resName, deficit := LargestDeficit(tt.available, tt.request)
+			assert.Equal(t, resName, tt.name, "unexpected type")
+			assert.Equal(t, deficit, tt.deficit, "unexpected deficit")
+			assert.Equal(t, tt.available.FitIn(tt.request), deficit == 0, "deficit does not match FitIn")
+		})
+	}
+}
+
+func TestFitInNonZeroCapacity(t *testing.T) {
+	capacity := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 0, "third": -5})
+	tests := map[string]struct {
+		usage    *Resource
+		capacity *Resource
+		expected bool
+	}{
+		"nil usage":          {usage: nil, capacity: capacity, expected: true},
+		"nil capacity":       {usage: NewResourceFromMap(map[string]Quantity{"first": 100}), capacity: nil, expected: true},
+		"fits":               {usage: NewResourceFromMap(map[string]Quantity{"first": 10}), capacity: capacity, expected: true},
+		"no fit":             {usage: NewResourceFromMap(map[string]Quantity{"first": 11}), capacity: capacity, expected: false},
+		"zero capacity":      {usage: NewResourceFromMap(map[string]Quantity{"first": 5, "second": 100}), capacity: capacity, expected: true},
+		"negative capacity":  {usage: NewResourceFromMap(map[string]Quantity{"third": 100}), capacity: capacity, expected: true},
+		"undefined capacity": {usage: NewResourceFromMap(map[string]Quantity{"fourth": 100}), capacity: capacity, expected: true},
+		"one type no fit":    {usage: NewResourceFromMap(map[string]Quantity{"first": 11, "second": 1}), capacity: capacity, expected: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.usage.FitInNonZeroCapacity(tt.capacity), tt.expected, "unexpected fit result")
+		})
+	}
+}
+
+func TestFitInDetailed(t *testing.T) {
+	tests := []struct {
+		name    string
+		larger  *Resource
+		smaller *Resource
+		want    []string
+	}{
+		{"nil larger", nil, NewResource(), []string{}},
+		{"nil larger set smaller", nil, NewResourceFromMap(map[string]Quantity{"a": 1}), []string{"a"}},
+		{"nil smaller", NewResource(), nil, []string{}},
+		{"zero set", NewResource(), NewResourceFromMap(map[string]Quantity{"a": 1}), []string{"a"}},
+		{"same type", NewResourceFromMap(map[string]Quantity{"a": 5}), NewResourceFromMap(map[string]Quantity{"a": 1}), []string{}},
+		{"not in larger", NewResourceFromMap(map[string]Quantity{"not-in-smaller": 1}), NewResourceFromMap(map[string]Quantity{"not-in-larger": 1}), []string{"not-in-larger"}},
+		{"negative larger", NewResourceFromMap(map[string]Quantity{"a": -10}), NewResourceFromMap(map[string]Quantity{"a": 0, "b": -10}), []string{}},
+		{"negative smaller", NewResourceFromMap(map[string]Quantity{"a": -5}), NewResourceFromMap(map[string]Quantity{"a": 0, "b": 10}), []string{"b"}},
+		{"multiple sorted", NewResourceFromMap(map[string]Quantity{"a": 1, "b": 1, "c": 1}), NewResourceFromMap(map[string]Quantity{"c": 2, "b": 1, "a": 2}), []string{"a", "c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit, failed := tt.larger.FitInDetailed(tt.smaller)
+			assert.Equal(t, fit, tt.larger.FitIn(tt.smaller), "FitInDetailed result differs from FitIn")
+			assert.DeepEqual(t, failed, tt.want)
+		})
+	}
+}
+
+func TestFitInExplain(t *testing.T) {
+	tests := map[string]struct {
+		larger  *Resource
+		request *Resource
+		want    []FitFailure
+	}{
+		"nil larger":        {larger: nil, request: NewResource(), want: []FitFailure{}},
+		"nil larger set":    {larger: nil, request: NewResourceFromMap(map[string]Quantity{"a": 1}), want: []FitFailure{{Type: "a", Available: 0, Requested: 1, Deficit: 1}}},
+		"nil request":       {larger: NewResource(), request: nil, want: []FitFailure{}},
+		"fits":              {larger: NewResourceFromMap(map[string]Quantity{"a": 5}), request: NewResourceFromMap(map[string]Quantity{"a": 5}), want: []FitFailure{}},
+		"not in larger":     {larger: NewResourceFromMap(map[string]Quantity{"a": 1}), request: NewResourceFromMap(map[string]Quantity{"b": 3}), want: []FitFailure{{Type: "b", Available: 0, Requested: 3, Deficit: 3}}},
+		"negative larger":   {larger: NewResourceFromMap(map[string]Quantity{"a": -10}), request: NewResourceFromMap(map[string]Quantity{"a": 2, "b": -10}), want: []FitFailure{{Type: "a", Available: 0, Requested: 2, Deficit: 2}}},
+		"multiple sorted":   {larger: NewResourceFromMap(map[string]Quantity{"a": 1, "b": 1, "c": 1}), request: NewResourceFromMap(map[string]Quantity{"c": 4, "b": 1, "a": 2}), want: []FitFailure{{Type: "a", Available: 1, Requested: 2, Deficit: 1}, {Type: "c", Available: 1, Requested: 4, Deficit: 3}}},
+		"maximum requested": {larger: NewResource(), request: NewResourceFromMap(map[string]Quantity{"a": math.MaxInt64}), want: []FitFailure{{Type: "a", Available: 0, Requested: math.MaxInt64, Deficit: math.MaxInt64}}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fit, failures := tt.larger.FitInExplain(tt.request)
+			assert.Equal(t, fit, tt.larger.FitIn(tt.request), "FitInExplain result differs from FitIn")
+			assert.DeepEqual(t, failures, tt.want)
+			_, failed := tt.larger.FitInDetailed(tt.request)
+			assert.Equal(t, len(failed), len(failures), "FitInExplain types differ from FitInDetailed")
+		})
+	}
+}
+
+func TestFitInRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		larger  *Resource
+		request *Resource
+		want    float64
+	}{
+		{"nil larger", nil, NewResourceFromMap(map[string]Quantity{"a": 1}), 0},
+		{"nil larger nil request", nil, nil, 1},
+		{"nil request", NewResourceFromMap(map[string]Quantity{"a": 1}), nil, 1},
+		{"empty request", NewResource(), NewResource(), 1},
+		{"fits", NewResourceFromMap(map[string]Quantity{"a": 10, "b": 10}), NewResourceFromMap(map[string]Quantity{"a": 5, "b": 10}), 1},
+		{"half fits", NewResourceFromMap(map[string]Quantity{"a": 10, "b": 10}), NewResourceFromMap(map[string]Quantity{"a": 20, "b": 10}), 0.5},
+		{"tightest type", NewResourceFromMap(map[string]Quantity{"a": 10, "b": 10}), NewResourceFromMap(map[string]Quantity{"a": 20, "b": 40}), 0.25},
+		{"missing type", NewResourceFromMap(map[string]Quantity{"a": 10}), NewResourceFromMap(map[string]Quantity{"a": 5, "b": 1}), 0},
+		{"negative larger", NewResourceFromMap(map[string]Quantity{"a": -10}), NewResourceFromMap(map[string]Quantity{"a": 5}), 0},
+		{"zero and negative request skipped", NewResource(), NewResourceFromMap(map[string]Quantity{"a": 0, "b": -5}), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ratio := tt.larger.FitInRatio(tt.request)
+			assert.Equal(t, ratio, tt.want, "unexpected FitInRatio result")
+			assert.Equal(t, ratio == 1, tt.larger.FitIn(tt.request), "FitInRatio and FitIn disagree")
+		})
+	}
+}
+
 // simple cases (nil checks)
 func TestFinInNil(t *testing.T) {
 	defer func() {
@@ -1314,6 +3148,153 @@ func TestFitInSkip(t *testing.T) {
 	}
 }
 
+func TestScaleToFit(t *testing.T) {
+	tests := map[string]struct {
+		request, available *Resource
+		expected           *Resource
+		ratio              float64
+	}{
+		"nil request": {
+			available: NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected:  NewResource(),
+			ratio:     1.0,
+		},
+		"nil available": {
+			request:  NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected: NewResource(),
+			ratio:    0.0,
+		},
+		"fits": {
+			request:   NewResourceFromMap(map[string]Quantity{"first": 5, "second": 10}),
+			available: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}),
+			expected:  NewResourceFromMap(map[string]Quantity{"first": 5, "second": 10}),
+			ratio:     1.0,
+		},
+		"zero and negative request fit": {
+			request:   NewResourceFromMap(map[string]Quantity{"first": 0, "second": -10}),
+			available: NewResource(),
+			expected:  NewResourceFromMap(map[string]Quantity{"first": 0, "second": -10}),
+			ratio:     1.0,
+		},
+		"single type scaled": {
+			request:   NewResourceFromMap(map[string]Quantity{"first": 20, "second": 10}),
+			available: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}),
+			expected:  NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}),
+			ratio:     0.5,
+		},
+		"tightest type used": {
+			request:   NewResourceFromMap(map[string]Quantity{"first": 20, "second": 40}),
+			available: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}),
+			expected:  NewResourceFromMap(map[string]Quantity{"first": 5, "second": 10}),
+			ratio:     0.25,
+		},
+		"zero available": {
+			request:   NewResourceFromMap(map[string]Quantity{"first": 20, "second": 10}),
+			available: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 0}),
+			expected:  NewResource(),
+			ratio:     0.0,
+		},
+		"negative available": {
+			request:   NewResourceFromMap(map[string]Quantity{"first": 20}),
+			available: NewResourceFromMap(map[string]Quantity{"first": -10}),
+			expected:  NewResource(),
+			ratio:     0.0,
+		},
+		"missing available type": {
+			request:   NewResourceFromMap(map[string]Quantity{"first": 20, "second": 10}),
+			available: NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected:  NewResource(),
+			ratio:     0.0,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			scaled, ratio := ScaleToFit(test.request, test.available)
+			assert.Equal(t, ratio, test.ratio, "unexpected ratio")
+			assert.Assert(t, DeepEquals(scaled, test.expected), "unexpected scaled resource: %v", scaled)
+			assert.Assert(t, test.available.FitIn(scaled), "scaled resource does not fit")
+		})
+	}
+}
+
+func TestFitInCount(t *testing.T) {
+	capacity := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 100})
+	tests := map[string]struct {
+		capacity *Resource
+		used     *Resource
+		request  *Resource
+		expected int64
+	}{
+		"nil request":        {capacity: capacity, used: nil, request: nil, expected: math.MaxInt64},
+		"nil capacity":       {capacity: nil, used: nil, request: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: math.MaxInt64},
+		"nil used":           {capacity: capacity, used: nil, request: NewResourceFromMap(map[string]Quantity{"first": 3}), expected: 3},
+		"with used":          {capacity: capacity, used: NewResourceFromMap(map[string]Quantity{"first": 4}), request: NewResourceFromMap(map[string]Quantity{"first": 3}), expected: 2},
+		"tightest type":      {capacity: capacity, used: NewResourceFromMap(map[string]Quantity{"second": 90}), request: NewResourceFromMap(map[string]Quantity{"first": 1, "second": 5}), expected: 2},
+		"does not fit":       {capacity: capacity, used: nil, request: NewResourceFromMap(map[string]Quantity{"first": 11}), expected: 0},
+		"over used":          {capacity: capacity, used: NewResourceFromMap(map[string]Quantity{"first": 20}), request: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: 0},
+		"undefined capacity": {capacity: capacity, used: nil, request: NewResourceFromMap(map[string]Quantity{"first": 5, "third": 100}), expected: 2},
+		"all undefined":      {capacity: capacity, used: nil, request: NewResourceFromMap(map[string]Quantity{"third": 100}), expected: math.MaxInt64},
+		"zero request":       {capacity: capacity, used: nil, request: NewResourceFromMap(map[string]Quantity{"first": 0, "second": 50}), expected: 2},
+		"negative request":   {capacity: capacity, used: nil, request: NewResourceFromMap(map[string]Quantity{"first": -1}), expected: math.MaxInt64},
+		"zero capacity":      {capacity: NewResourceFromMap(map[string]Quantity{"first": 0}), used: nil, request: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, FitInCount(tt.capacity, tt.used, tt.request), tt.expected, "unexpected count")
+		})
+	}
+}
+
+func TestSplitByWeights(t *testing.T) {
+	tests := map[string]struct {
+		total    *Resource
+		weights  map[string]float64
+		expected map[string]map[string]Quantity
+		split    bool
+	}{
+		"nil weights": {total: NewResourceFromMap(map[string]Quantity{"first": 10}), weights: nil, expected: map[string]map[string]Quantity{}},
+		"nil total": {total: nil, weights: map[string]float64{"a": 1, "b": 1}, expected: map[string]map[string]Quantity{
+			"a": {}, "b": {},
+		}},
+		"no positive weight": {total: NewResourceFromMap(map[string]Quantity{"first": 10}), weights: map[string]float64{"a": 0, "b": -1}, expected: map[string]map[string]Quantity{
+			"a": {}, "b": {},
+		}},
+		"even split": {total: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 4}), weights: map[string]float64{"a": 1, "b": 1}, expected: map[string]map[string]Quantity{
+			"a": {"first": 5, "second": 2}, "b": {"first": 5, "second": 2},
+		}, split: true},
+		"weighted split": {total: NewResourceFromMap(map[string]Quantity{"first": 100}), weights: map[string]float64{"a": 3, "b": 1, "zero": 0}, expected: map[string]map[string]Quantity{
+			"a": {"first": 75}, "b": {"first": 25}, "zero": {},
+		}, split: true},
+		"tied remainders": {total: NewResourceFromMap(map[string]Quantity{"first": 10}), weights: map[string]float64{"c": 1, "b": 1, "a": 1}, expected: map[string]map[string]Quantity{
+			"a": {"first": 4}, "b": {"first": 3}, "c": {"first": 3},
+		}, split: true},
+		"largest remainder": {total: NewResourceFromMap(map[string]Quantity{"first": 10}), weights: map[string]float64{"a": 1, "b": 2, "c": 4}, expected: map[string]map[string]Quantity{
+			// exact shares: 1.43, 2.86, 5.71
+			"a": {"first": 1}, "b": {"first": 3}, "c": {"first": 6},
+		}, split: true},
+		"negative total": {total: NewResourceFromMap(map[string]Quantity{"first": -10}), weights: map[string]float64{"a": 1, "b": 1, "c": 1}, expected: map[string]map[string]Quantity{
+			"a": {"first": -3}, "b": {"first": -3}, "c": {"first": -4},
+		}, split: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			shares := SplitByWeights(tt.total, tt.weights)
+			assert.Equal(t, len(shares), len(tt.expected), "unexpected number of shares")
+			for share, expected := range tt.expected {
+				assert.Assert(t, shares[share] != nil, "share %s missing", share)
+				assert.DeepEqual(t, shares[share].Resources, expected)
+			}
+			if tt.split {
+				sum := NewResource()
+				for _, share := range shares {
+					sum.AddTo(share)
+				}
+				assert.Assert(t, Equals(sum, tt.total), "shares do not sum up to the total: got %v, expected %v", sum, tt.total)
+			}
+		})
+	}
+}
+
 //nolint:funlen // thorough test
 func TestGetFairShare(t *testing.T) {
 	// 0 guarantee should be treated as absence of a gurantee
@@ -1472,6 +3453,7 @@ func TestGetFairShare(t *testing.T) {
 			if !reflect.DeepEqual(share, tc.expected) {
 				t.Errorf("incorrect share for allocated( %s ), guaranteed( %s ), fairmax( %s ) expected %v got: %v", tc.allocated, tc.guaranteed, tc.fairmax, tc.expected, share)
 			}
+			assert.Equal(t, FairShare(tc.allocated, tc.guaranteed, tc.fairmax), share, "exported fair share differs from internal calculation")
 		})
 	}
 }
@@ -1551,15 +3533,101 @@ func TestGetShares(t *testing.T) {
 			if !reflect.DeepEqual(shares, tc.expected) {
 				t.Errorf("incorrect shares for %s, expected %v got: %v", tc.message, tc.expected, shares)
 			}
+			assert.DeepEqual(t, SharesOf(tc.res, tc.total), shares)
 		})
 	}
 }
 
-func TestCompUsageRatio(t *testing.T) {
-	tests := []struct {
-		left     *Resource
-		right    *Resource
-		total    *Resource
+func TestRatioVector(t *testing.T) {
+	tests := map[string]struct {
+		res, total *Resource
+		expected   map[string]float64
+	}{
+		"nil resource": {
+			total:    NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected: map[string]float64{},
+		},
+		"empty resource": {
+			res:      NewResource(),
+			total:    NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected: map[string]float64{},
+		},
+		"nil total": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": 10, "second": -5, "zero": 0}),
+			expected: map[string]float64{"first": 10, "second": -5, "zero": 0},
+		},
+		"zero total": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}),
+			total:    NewResourceFromMap(map[string]Quantity{"first": 0, "second": 10}),
+			expected: map[string]float64{"first": 10, "second": 0.5},
+		},
+		"partial total": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}),
+			total:    NewResourceFromMap(map[string]Quantity{"first": 20}),
+			expected: map[string]float64{"first": 0.5, "second": 5},
+		},
+		"negative usage": {
+			res:      NewResourceFromMap(map[string]Quantity{"first": -10, "second": 30}),
+			total:    NewResourceFromMap(map[string]Quantity{"first": 20, "second": 20}),
+			expected: map[string]float64{"first": -0.5, "second": 1.5},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ratios := test.res.RatioVector(test.total)
+			assert.DeepEqual(t, ratios, test.expected)
+			// must match the shares for the same input
+			shares := make([]float64, 0, len(ratios))
+			for _, v := range ratios {
+				shares = append(shares, v)
+			}
+			sort.Float64s(shares)
+			assert.DeepEqual(t, shares, getShares(test.res, test.total))
+		})
+	}
+}
+
+func TestOvercommitRatio(t *testing.T) {
+	tests := map[string]struct {
+		committed, guaranteed *Resource
+		expected              map[string]float64
+	}{
+		"nil committed": {
+			guaranteed: NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected:   map[string]float64{},
+		},
+		"nil guaranteed": {
+			committed: NewResourceFromMap(map[string]Quantity{"first": 10}),
+			expected:  map[string]float64{},
+		},
+		"below guaranteed": {
+			committed:  NewResourceFromMap(map[string]Quantity{"first": 5, "second": 0}),
+			guaranteed: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}),
+			expected:   map[string]float64{"first": 0.5, "second": 0},
+		},
+		"overcommitted": {
+			committed:  NewResourceFromMap(map[string]Quantity{"first": 30, "second": 10}),
+			guaranteed: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}),
+			expected:   map[string]float64{"first": 3, "second": 1},
+		},
+		"zero or missing guaranteed": {
+			committed:  NewResourceFromMap(map[string]Quantity{"first": 30, "second": 10}),
+			guaranteed: NewResourceFromMap(map[string]Quantity{"first": 0}),
+			expected:   map[string]float64{"first": 30, "second": 10},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, OvercommitRatio(test.committed, test.guaranteed), test.expected)
+		})
+	}
+}
+
+func TestCompUsageRatio(t *testing.T) {
+	tests := []struct {
+		left     *Resource
+		right    *Resource
+		total    *Resource
 		expected int
 		message  string
 	}{
@@ -1659,6 +3727,29 @@ func TestCompUsageRatio(t *testing.T) {
 	}
 }
 
+func TestCompareByCapacityShare(t *testing.T) {
+	capacity := NewResourceFromMap(map[string]Quantity{"A": 100, "B": 10})
+	tests := map[string]struct {
+		left, right *Resource
+		capacity    *Resource
+		expected    int
+	}{
+		"both nil":        {left: nil, right: nil, capacity: capacity, expected: 0},
+		"nil left":        {left: nil, right: NewResourceFromMap(map[string]Quantity{"A": 1}), capacity: capacity, expected: -1},
+		"left larger":     {left: NewResourceFromMap(map[string]Quantity{"A": 10, "B": 5}), right: NewResourceFromMap(map[string]Quantity{"A": 40, "B": 1}), capacity: capacity, expected: 1},
+		"right larger":    {left: NewResourceFromMap(map[string]Quantity{"A": 40, "B": 1}), right: NewResourceFromMap(map[string]Quantity{"A": 10, "B": 5}), capacity: capacity, expected: -1},
+		"norm tie break":  {left: NewResourceFromMap(map[string]Quantity{"A": 30, "B": 2}), right: NewResourceFromMap(map[string]Quantity{"A": 30}), capacity: capacity, expected: 1},
+		"equal":           {left: NewResourceFromMap(map[string]Quantity{"A": 30}), right: NewResourceFromMap(map[string]Quantity{"A": 30}), capacity: capacity, expected: 0},
+		"undefined types": {left: NewResourceFromMap(map[string]Quantity{"C": 1000}), right: NewResourceFromMap(map[string]Quantity{"A": 1}), capacity: capacity, expected: -1},
+		"nil capacity":    {left: NewResourceFromMap(map[string]Quantity{"A": 10}), right: NewResourceFromMap(map[string]Quantity{"A": 5}), capacity: nil, expected: 1},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, CompareByCapacityShare(tt.left, tt.right, tt.capacity), tt.expected)
+		})
+	}
+}
+
 func TestCompareShares(t *testing.T) {
 	tests := []struct {
 		left     []float64
@@ -1746,6 +3837,7 @@ func TestCompareShares(t *testing.T) {
 			if comp != tc.expected {
 				t.Errorf("incorrect comparison for %s: expected %d got: %d", tc.message, tc.expected, comp)
 			}
+			assert.Equal(t, CompareShareVectors(tc.left, tc.right), comp, "exported compare differs from internal compare")
 		})
 	}
 }
@@ -2031,6 +4123,253 @@ func TestFitInScore(t *testing.T) {
 	}
 }
 
+func TestFitInScoreNormalized(t *testing.T) {
+	fit := NewResourceFromMap(map[string]Quantity{"first": 100, "second": 10, "zero": 0, "negative": -10})
+	tests := map[string]struct {
+		res      *Resource
+		fit      *Resource
+		expected float64
+	}{
+		"nil fit":           {res: NewResource(), fit: nil, expected: 0},
+		"empty fit":         {res: NewResource(), fit: NewResource(), expected: 0},
+		"non positive fit":  {res: nil, fit: NewResourceFromMap(map[string]Quantity{"zero": 0, "negative": -1}), expected: 0},
+		"nil receiver":      {res: nil, fit: fit, expected: 1},
+		"empty receiver":    {res: NewResource(), fit: fit, expected: 1},
+		"perfect fit":       {res: NewResourceFromMap(map[string]Quantity{"first": 100, "second": 10}), fit: fit, expected: 0},
+		"larger than fit":   {res: NewResourceFromMap(map[string]Quantity{"first": 200, "second": 20}), fit: fit, expected: 0},
+		"half fit":          {res: NewResourceFromMap(map[string]Quantity{"first": 50, "second": 5}), fit: fit, expected: 0.5},
+		"single type fit":   {res: NewResourceFromMap(map[string]Quantity{"first": 100}), fit: fit, expected: 0.5},
+		"single type score": {res: NewResourceFromMap(map[string]Quantity{"first": 25}), fit: NewResourceFromMap(map[string]Quantity{"first": 100}), expected: 0.75},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.res.FitInScoreNormalized(tt.fit), tt.expected, "unexpected normalized score")
+		})
+	}
+}
+
+func TestFitsAnyAll(t *testing.T) {
+	small := NewResourceFromMap(map[string]Quantity{"first": 5})
+	large := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10})
+	request := NewResourceFromMap(map[string]Quantity{"first": 8})
+	tests := map[string]struct {
+		request    *Resource
+		capacities []*Resource
+		any        bool
+		all        bool
+	}{
+		"nil list":       {request: request, capacities: nil, any: false, all: true},
+		"empty list":     {request: request, capacities: []*Resource{}, any: false, all: true},
+		"fits none":      {request: request, capacities: []*Resource{small, nil}, any: false, all: false},
+		"fits one":       {request: request, capacities: []*Resource{small, large}, any: true, all: false},
+		"fits all":       {request: request, capacities: []*Resource{large, large}, any: true, all: true},
+		"nil request":    {request: nil, capacities: []*Resource{small, nil}, any: true, all: true},
+		"undefined type": {request: NewResourceFromMap(map[string]Quantity{"third": 1}), capacities: []*Resource{small, large}, any: false, all: false},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, FitsAny(tt.request, tt.capacities), tt.any, "unexpected FitsAny result")
+			assert.Equal(t, FitsAll(tt.request, tt.capacities), tt.all, "unexpected FitsAll result")
+		})
+	}
+}
+
+func TestWeightedSum(t *testing.T) {
+	weights := map[string]float64{common.CPU: 0.5, common.Memory: 2, "zero": 0}
+	tests := map[string]struct {
+		res      *Resource
+		weights  map[string]float64
+		expected float64
+	}{
+		"nil resource":   {res: nil, weights: weights, expected: 0},
+		"empty resource": {res: NewResource(), weights: weights, expected: 0},
+		"nil weights":    {res: NewResourceFromMap(map[string]Quantity{common.CPU: 10}), weights: nil, expected: 0},
+		"single type":    {res: NewResourceFromMap(map[string]Quantity{common.CPU: 10}), weights: weights, expected: 5},
+		"multiple types": {res: NewResourceFromMap(map[string]Quantity{common.CPU: 10, common.Memory: 10}), weights: weights, expected: 25},
+		"unlisted type":  {res: NewResourceFromMap(map[string]Quantity{common.Memory: 10, "other": 100}), weights: weights, expected: 20},
+		"zero weight":    {res: NewResourceFromMap(map[string]Quantity{"zero": 100}), weights: weights, expected: 0},
+		"negative value": {res: NewResourceFromMap(map[string]Quantity{common.CPU: -10, common.Memory: 10}), weights: weights, expected: 15},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.res.WeightedSum(tt.weights), tt.expected, "unexpected weighted sum")
+		})
+	}
+}
+
+func TestL1Norm(t *testing.T) {
+	tests := map[string]struct {
+		res      *Resource
+		expected int64
+	}{
+		"nil resource":    {res: nil, expected: 0},
+		"empty resource":  {res: NewResource(), expected: 0},
+		"positive values": {res: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), expected: 15},
+		"negative values": {res: NewResourceFromMap(map[string]Quantity{"first": -10, "second": 5}), expected: 15},
+		"overflow":        {res: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64, "second": 1}), expected: math.MaxInt64},
+		"minimum value":   {res: NewResourceFromMap(map[string]Quantity{"first": math.MinInt64}), expected: math.MaxInt64},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.res.L1Norm(), tt.expected, "unexpected norm")
+		})
+	}
+}
+
+func TestComposition(t *testing.T) {
+	tests := map[string]struct {
+		res      *Resource
+		expected map[string]float64
+	}{
+		"nil resource":    {res: nil, expected: map[string]float64{}},
+		"empty resource":  {res: NewResource(), expected: map[string]float64{}},
+		"zero values":     {res: NewResourceFromMap(map[string]Quantity{"first": 0, "second": 0}), expected: map[string]float64{}},
+		"single type":     {res: NewResourceFromMap(map[string]Quantity{"first": 5}), expected: map[string]float64{"first": 1}},
+		"zero type":       {res: NewResourceFromMap(map[string]Quantity{"first": 5, "second": 0}), expected: map[string]float64{"first": 1, "second": 0}},
+		"positive values": {res: NewResourceFromMap(map[string]Quantity{"first": 30, "second": 10}), expected: map[string]float64{"first": 0.75, "second": 0.25}},
+		"negative values": {res: NewResourceFromMap(map[string]Quantity{"first": -30, "second": 10}), expected: map[string]float64{"first": 0.75, "second": 0.25}},
+		"large values":    {res: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64, "second": math.MaxInt64}), expected: map[string]float64{"first": 0.5, "second": 0.5}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			result := tt.res.Composition()
+			assert.DeepEqual(t, result, tt.expected)
+			var sum float64
+			for _, v := range result {
+				sum += v
+			}
+			if len(tt.expected) != 0 {
+				assert.Equal(t, sum, 1.0, "fractions should add up to 1")
+			}
+		})
+	}
+}
+
+func TestMaxComponent(t *testing.T) {
+	tests := map[string]struct {
+		res   *Resource
+		name  string
+		value Quantity
+		found bool
+	}{
+		"nil resource":    {res: nil, name: "", value: 0, found: false},
+		"empty resource":  {res: NewResource(), name: "", value: 0, found: false},
+		"single type":     {res: NewResourceFromMap(map[string]Quantity{"first": 0}), name: "first", value: 0, found: true},
+		"largest":         {res: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 5}), name: "first", value: 10, found: true},
+		"negative":        {res: NewResourceFromMap(map[string]Quantity{"first": 10, "second": -15}), name: "second", value: -15, found: true},
+		"tie":             {res: NewResourceFromMap(map[string]Quantity{"second": 10, "first": -10}), name: "first", value: -10, found: true},
+		"empty type name": {res: NewResourceFromMap(map[string]Quantity{"": 0}), name: "", value: 0, found: true},
+		"minimum value":   {res: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64, "second": math.MinInt64}), name: "second", value: math.MinInt64, found: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resName, value, found := tt.res.MaxComponent()
+			assert.Equal(t, found, tt.found, "unexpected found flag")
+			assert.Equal(t, resName, tt.name, "unexpected type")
+			assert.Equal(t, value, tt.value, "unexpected value")
+		})
+	}
+}
+
+func TestMaxMin(t *testing.T) {
+	tests := map[string]struct {
+		res *Resource
+		max Quantity
+		min Quantity
+	}{
+		"nil resource":   {res: nil, max: 0, min: 0},
+		"empty resource": {res: NewResource(), max: 0, min: 0},
+		"single type":    {res: NewResourceFromMap(map[string]Quantity{"first": 5}), max: 5, min: 5},
+		"positive":       {res: NewResourceFromMap(map[string]Quantity{"first": 5, "second": 10, "third": 1}), max: 10, min: 1},
+		"negative":       {res: NewResourceFromMap(map[string]Quantity{"first": -5, "second": -10}), max: -5, min: -10},
+		"extremes":       {res: NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64, "second": 0, "third": math.MinInt64}), max: math.MaxInt64, min: math.MinInt64},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.res.Max(), tt.max, "unexpected max")
+			assert.Equal(t, tt.res.Min(), tt.min, "unexpected min")
+		})
+	}
+}
+
+func TestClosestFit(t *testing.T) {
+	request := NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10})
+	tests := map[string]struct {
+		request    *Resource
+		candidates []*Resource
+		index      int
+		score      float64
+	}{
+		"nil candidates": {
+			request: request,
+			index:   -1,
+		},
+		"no fit": {
+			request: request,
+			candidates: []*Resource{
+				nil,
+				NewResourceFromMap(map[string]Quantity{"first": 5, "second": 100}),
+				NewResourceFromMap(map[string]Quantity{"first": 100}),
+			},
+			index: -1,
+		},
+		"single fit": {
+			request: request,
+			candidates: []*Resource{
+				NewResourceFromMap(map[string]Quantity{"first": 5, "second": 100}),
+				NewResourceFromMap(map[string]Quantity{"first": 20, "second": 20}),
+			},
+			index: 1,
+			score: 1,
+		},
+		"exact fit preferred": {
+			request: request,
+			candidates: []*Resource{
+				NewResourceFromMap(map[string]Quantity{"first": 20, "second": 20}),
+				NewResourceFromMap(map[string]Quantity{"first": 10, "second": 10}),
+				NewResourceFromMap(map[string]Quantity{"first": 100, "second": 100}),
+			},
+			index: 1,
+			score: 0,
+		},
+		"closest fit": {
+			request: request,
+			candidates: []*Resource{
+				NewResourceFromMap(map[string]Quantity{"first": 100, "second": 100}),
+				NewResourceFromMap(map[string]Quantity{"first": 20, "second": 10}),
+				NewResourceFromMap(map[string]Quantity{"first": 20, "second": 20}),
+			},
+			index: 1,
+			score: 0.5,
+		},
+		"first of equal scores": {
+			request: request,
+			candidates: []*Resource{
+				NewResourceFromMap(map[string]Quantity{"first": 5}),
+				NewResourceFromMap(map[string]Quantity{"first": 20, "second": 20}),
+				NewResourceFromMap(map[string]Quantity{"first": 20, "second": 20}),
+			},
+			index: 1,
+			score: 1,
+		},
+		"nil request": {
+			candidates: []*Resource{
+				NewResourceFromMap(map[string]Quantity{"first": 20, "second": 20}),
+				nil,
+			},
+			index: 1,
+			score: 0,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			index, score := ClosestFit(test.request, test.candidates)
+			assert.Equal(t, index, test.index, "unexpected candidate selected")
+			assert.Equal(t, score, test.score, "unexpected score")
+		})
+	}
+}
+
 func TestCalculateAbsUsedCapacity(t *testing.T) {
 	zeroResource := NewResourceFromMap(map[string]Quantity{"memory": 0, "vcores": 0})
 	resourceSet := NewResourceFromMap(map[string]Quantity{"memory": 2048, "vcores": 3})
@@ -2111,6 +4450,209 @@ func TestCalculateAbsUsedCapacity(t *testing.T) {
 	}
 }
 
+func TestCalculateAbsUsedCapacityFloat(t *testing.T) {
+	zeroResource := NewResourceFromMap(map[string]Quantity{"memory": 0, "vcores": 0})
+	resourceSet := NewResourceFromMap(map[string]Quantity{"memory": 2048, "vcores": 8})
+	usageSet := NewResourceFromMap(map[string]Quantity{"memory": 1024, "vcores": 1})
+	partialResource := NewResourceFromMap(map[string]Quantity{"memory": 1024})
+
+	tests := map[string]struct {
+		capacity, used *Resource
+		expected       map[string]float64
+	}{
+		"nil resource, nil usage": {
+			expected: map[string]float64{},
+		},
+		"resource set, nil usage": {
+			capacity: resourceSet,
+			expected: map[string]float64{},
+		},
+		"resource set, zero usage": {
+			capacity: resourceSet,
+			used:     zeroResource,
+			expected: map[string]float64{"memory": 0, "vcores": 0},
+		},
+		"resource set, usage set": {
+			capacity: resourceSet,
+			used:     usageSet,
+			expected: map[string]float64{"memory": 50, "vcores": 12.5},
+		},
+		"partial resource set, usage set": {
+			capacity: partialResource,
+			used:     usageSet,
+			expected: map[string]float64{"memory": 100},
+		},
+		"resource set, partial usage set": {
+			capacity: resourceSet,
+			used:     partialResource,
+			expected: map[string]float64{"memory": 50},
+		},
+		"no truncation": {
+			capacity: NewResourceFromMap(map[string]Quantity{"memory": 1000}),
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 998}),
+			expected: map[string]float64{"memory": 99.8},
+		},
+		"no cap": {
+			capacity: NewResourceFromMap(map[string]Quantity{"memory": 1}),
+			used:     NewResourceFromMap(map[string]Quantity{"memory": math.MaxInt64}),
+			expected: map[string]float64{"memory": float64(math.MaxInt64) * 100},
+		},
+		"negative usage": {
+			capacity: NewResourceFromMap(map[string]Quantity{"memory": 10}),
+			used:     NewResourceFromMap(map[string]Quantity{"memory": math.MinInt64}),
+			expected: map[string]float64{"memory": 0},
+		},
+		"zero resource, non zero used": {
+			capacity: zeroResource,
+			used:     usageSet,
+			expected: map[string]float64{"memory": 100, "vcores": 100},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			absCapacity := CalculateAbsUsedCapacityFloat(test.capacity, test.used)
+			assert.DeepEqual(t, test.expected, absCapacity)
+		})
+	}
+}
+
+func TestPercentOf(t *testing.T) {
+	tests := map[string]struct {
+		used, total *Resource
+		expected    map[string]float64
+	}{
+		"nil used": {
+			total:    NewResourceFromMap(map[string]Quantity{"memory": 10}),
+			expected: map[string]float64{},
+		},
+		"nil total": {
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 10, "vcores": 0}),
+			expected: map[string]float64{"memory": 1, "vcores": 0},
+		},
+		"fractions": {
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 998, "vcores": 1}),
+			total:    NewResourceFromMap(map[string]Quantity{"memory": 1000, "vcores": 4}),
+			expected: map[string]float64{"memory": 0.998, "vcores": 0.25},
+		},
+		"over total": {
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 20}),
+			total:    NewResourceFromMap(map[string]Quantity{"memory": 10}),
+			expected: map[string]float64{"memory": 2},
+		},
+		"zero and missing total": {
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 20, "vcores": 1, "pods": 0}),
+			total:    NewResourceFromMap(map[string]Quantity{"memory": 0, "pods": 0}),
+			expected: map[string]float64{"memory": 1, "vcores": 1, "pods": 0},
+		},
+		"negative values": {
+			used:     NewResourceFromMap(map[string]Quantity{"memory": -20, "vcores": 1}),
+			total:    NewResourceFromMap(map[string]Quantity{"memory": 10, "vcores": -10}),
+			expected: map[string]float64{"memory": 0, "vcores": 1},
+		},
+		"total types not used": {
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 5}),
+			total:    NewResourceFromMap(map[string]Quantity{"memory": 10, "vcores": 10}),
+			expected: map[string]float64{"memory": 0.5},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, test.used.PercentOf(test.total), test.expected)
+		})
+	}
+}
+
+func TestThresholdExceeded(t *testing.T) {
+	resourceSet := NewResourceFromMap(map[string]Quantity{"memory": 1000, "vcores": 10})
+	tests := map[string]struct {
+		capacity, used *Resource
+		percent        float64
+		exceeded       bool
+		name           string
+	}{
+		"nil capacity": {
+			used:    resourceSet,
+			percent: 50,
+		},
+		"nil usage": {
+			capacity: resourceSet,
+			percent:  0,
+		},
+		"below threshold": {
+			capacity: resourceSet,
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 500, "vcores": 5}),
+			percent:  50.1,
+		},
+		"at threshold": {
+			capacity: resourceSet,
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 500, "vcores": 1}),
+			percent:  50,
+			exceeded: true,
+			name:     "memory",
+		},
+		"fractional threshold": {
+			capacity: resourceSet,
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 998, "vcores": 1}),
+			percent:  99.5,
+			exceeded: true,
+			name:     "memory",
+		},
+		"first sorted type": {
+			capacity: resourceSet,
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 2000, "vcores": 20}),
+			percent:  100,
+			exceeded: true,
+			name:     "memory",
+		},
+		"zero usage": {
+			capacity: resourceSet,
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 0, "vcores": -1}),
+			percent:  0.1,
+		},
+		"zero capacity": {
+			capacity: NewResourceFromMap(map[string]Quantity{"memory": 1000, "vcores": 0}),
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 1, "vcores": 1}),
+			percent:  100,
+			exceeded: true,
+			name:     "vcores",
+		},
+		"usage not in capacity": {
+			capacity: NewResourceFromMap(map[string]Quantity{"memory": 1000}),
+			used:     NewResourceFromMap(map[string]Quantity{"memory": 1, "vcores": 100}),
+			percent:  50,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			exceeded, resType := ThresholdExceeded(test.capacity, test.used, test.percent)
+			assert.Equal(t, exceeded, test.exceeded, "unexpected threshold result")
+			assert.Equal(t, resType, test.name, "unexpected resource type returned")
+		})
+	}
+}
+
+func TestRemainingPercent(t *testing.T) {
+	tests := map[string]struct {
+		capacity *Resource
+		used     *Resource
+		expected map[string]float64
+	}{
+		"nil capacity":   {capacity: nil, used: NewResourceFromMap(map[string]Quantity{"first": 1}), expected: map[string]float64{}},
+		"nil used":       {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: nil, expected: map[string]float64{"first": 100}},
+		"partial":        {capacity: NewResourceFromMap(map[string]Quantity{"first": 10, "second": 8}), used: NewResourceFromMap(map[string]Quantity{"first": 5, "second": 1}), expected: map[string]float64{"first": 50, "second": 87.5}},
+		"fully used":     {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: NewResourceFromMap(map[string]Quantity{"first": 10}), expected: map[string]float64{"first": 0}},
+		"over used":      {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: NewResourceFromMap(map[string]Quantity{"first": 20}), expected: map[string]float64{"first": 0}},
+		"negative used":  {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: NewResourceFromMap(map[string]Quantity{"first": -10}), expected: map[string]float64{"first": 100}},
+		"zero capacity":  {capacity: NewResourceFromMap(map[string]Quantity{"first": 0, "second": -1}), used: nil, expected: map[string]float64{"first": 0, "second": 0}},
+		"only used type": {capacity: NewResourceFromMap(map[string]Quantity{"first": 10}), used: NewResourceFromMap(map[string]Quantity{"second": 10}), expected: map[string]float64{"first": 100}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, RemainingPercent(tt.capacity, tt.used), tt.expected)
+		})
+	}
+}
+
 func TestNewResourceFromString(t *testing.T) {
 	tests := map[string]struct {
 		jsonRes  string
@@ -2210,6 +4752,153 @@ func TestDAOMap(t *testing.T) {
 	}
 }
 
+func TestToMapNonZero(t *testing.T) {
+	var empty *Resource
+	assert.DeepEqual(t, empty.ToMapNonZero(), map[string]int64{})
+	assert.DeepEqual(t, NewResource().ToMapNonZero(), map[string]int64{})
+	assert.DeepEqual(t, NewResourceFromMap(map[string]Quantity{"zero": 0}).ToMapNonZero(), map[string]int64{})
+
+	res := NewResourceFromMap(map[string]Quantity{"first": 1, "zero": 0, "negative": -1})
+	assert.DeepEqual(t, res.ToMapNonZero(), map[string]int64{"first": 1, "negative": -1})
+	assert.DeepEqual(t, res.Resources, map[string]Quantity{"first": 1, "zero": 0, "negative": -1})
+}
+
+func TestToMap(t *testing.T) {
+	var empty *Resource
+	assert.DeepEqual(t, empty.ToMap(), map[string]Quantity{})
+	assert.DeepEqual(t, NewResource().ToMap(), map[string]Quantity{})
+
+	res := NewResourceFromMap(map[string]Quantity{"first": 10, "second": -10})
+	m := res.ToMap()
+	assert.DeepEqual(t, m, map[string]Quantity{"first": 10, "second": -10})
+	// changes to the copy must not leak into the resource
+	m["first"] = 1
+	m["third"] = 3
+	assert.Assert(t, maps.Equal(res.Resources, map[string]Quantity{"first": 10, "second": -10}), "resource changed via map copy")
+}
+
+func TestEntries(t *testing.T) {
+	var empty *Resource
+	assert.DeepEqual(t, empty.Entries(), []Entry{})
+	assert.DeepEqual(t, NewResource().Entries(), []Entry{})
+	res := NewResourceFromMap(map[string]Quantity{"third": 3, "first": 1, "second": 0})
+	assert.DeepEqual(t, res.Entries(), []Entry{{Type: "first", Value: 1}, {Type: "second", Value: 0}, {Type: "third", Value: 3}})
+	assert.Assert(t, DeepEquals(FromEntries(res.Entries()), res), "round trip changed the resource")
+}
+
+func TestFromEntries(t *testing.T) {
+	tests := map[string]struct {
+		entries  []Entry
+		expected map[string]Quantity
+	}{
+		"nil list":   {entries: nil, expected: map[string]Quantity{}},
+		"empty list": {entries: []Entry{}, expected: map[string]Quantity{}},
+		"unique":     {entries: []Entry{{Type: "first", Value: 1}, {Type: "second", Value: 0}}, expected: map[string]Quantity{"first": 1, "second": 0}},
+		"duplicates": {entries: []Entry{{Type: "first", Value: 1}, {Type: "second", Value: 2}, {Type: "first", Value: -3}}, expected: map[string]Quantity{"first": -2, "second": 2}},
+		"overflow":   {entries: []Entry{{Type: "first", Value: math.MaxInt64}, {Type: "first", Value: 1}}, expected: map[string]Quantity{"first": math.MaxInt64}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.DeepEqual(t, FromEntries(tt.entries).Resources, tt.expected)
+		})
+	}
+}
+
+func TestIterate(t *testing.T) {
+	var keys []string
+	var values []Quantity
+	collect := func(key string, value Quantity) bool {
+		keys = append(keys, key)
+		values = append(values, value)
+		return true
+	}
+	var empty *Resource
+	empty.Iterate(collect)
+	NewResource().Iterate(collect)
+	assert.Equal(t, len(keys), 0, "nil or empty resource should not call the function")
+
+	res := NewResourceFromMap(map[string]Quantity{"third": 3, "first": 1, "second": 2, "fourth": 4})
+	res.Iterate(collect)
+	assert.DeepEqual(t, keys, []string{"first", "fourth", "second", "third"})
+	assert.DeepEqual(t, values, []Quantity{1, 4, 2, 3})
+
+	// early exit
+	keys = nil
+	values = nil
+	res.Iterate(func(key string, _ Quantity) bool {
+		keys = append(keys, key)
+		return key != "fourth"
+	})
+	assert.DeepEqual(t, keys, []string{"first", "fourth"})
+}
+
+func TestGroupByPrefix(t *testing.T) {
+	res := NewResourceFromMap(map[string]Quantity{"hugepages-2Mi": 2, "hugepages-1Gi": 1, common.Memory: 10, "example.com/gpu-a": 3, "-leading": 4})
+	tests := map[string]struct {
+		res       *Resource
+		separator string
+		expected  map[string]map[string]Quantity
+	}{
+		"nil resource":   {res: nil, separator: "-", expected: map[string]map[string]Quantity{}},
+		"empty resource": {res: NewResource(), separator: "-", expected: map[string]map[string]Quantity{}},
+		"dash separator": {res: res, separator: "-", expected: map[string]map[string]Quantity{
+			"hugepages":       {"hugepages-2Mi": 2, "hugepages-1Gi": 1},
+			common.Memory:     {common.Memory: 10},
+			"example.com/gpu": {"example.com/gpu-a": 3},
+			"":                {"-leading": 4},
+		}},
+		"slash separator": {res: res, separator: "/", expected: map[string]map[string]Quantity{
+			"hugepages-2Mi": {"hugepages-2Mi": 2},
+			"hugepages-1Gi": {"hugepages-1Gi": 1},
+			common.Memory:   {common.Memory: 10},
+			"example.com":   {"example.com/gpu-a": 3},
+			"-leading":      {"-leading": 4},
+		}},
+		"empty separator": {res: NewResourceFromMap(map[string]Quantity{"hugepages-2Mi": 2, common.Memory: 10}), separator: "", expected: map[string]map[string]Quantity{
+			"hugepages-2Mi": {"hugepages-2Mi": 2},
+			common.Memory:   {common.Memory: 10},
+		}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			groups := tt.res.GroupByPrefix(tt.separator)
+			assert.Equal(t, len(groups), len(tt.expected), "unexpected number of groups")
+			for group, expected := range tt.expected {
+				assert.Assert(t, groups[group] != nil, "group %s missing", group)
+				assert.DeepEqual(t, groups[group].Resources, expected)
+			}
+		})
+	}
+}
+
+func TestCoalesceAliases(t *testing.T) {
+	aliases := map[string]string{"nvidia.com/gpu": "gpu", "amd.com/gpu": "gpu", "mem": common.Memory}
+	res := NewResourceFromMap(map[string]Quantity{"nvidia.com/gpu": 2, "amd.com/gpu": 3, "mem": 10, "pods": 5})
+	tests := map[string]struct {
+		res      *Resource
+		policy   MergePolicy
+		expected map[string]Quantity
+	}{
+		"empty resource": {res: NewResource(), policy: MergePolicySum, expected: map[string]Quantity{}},
+		"sum":            {res: res, policy: MergePolicySum, expected: map[string]Quantity{"gpu": 5, common.Memory: 10, "pods": 5}},
+		"max":            {res: res, policy: MergePolicyMax, expected: map[string]Quantity{"gpu": 3, common.Memory: 10, "pods": 5}},
+		"first":          {res: res, policy: MergePolicyFirst, expected: map[string]Quantity{"gpu": 3, common.Memory: 10, "pods": 5}},
+		"canonical and alias": {res: NewResourceFromMap(map[string]Quantity{"gpu": 1, "nvidia.com/gpu": 4}), policy: MergePolicyFirst,
+			expected: map[string]Quantity{"gpu": 1}},
+		"sum overflow": {res: NewResourceFromMap(map[string]Quantity{"nvidia.com/gpu": math.MaxInt64, "amd.com/gpu": 1}), policy: MergePolicySum,
+			expected: map[string]Quantity{"gpu": math.MaxInt64}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := tt.res.CoalesceAliases(aliases, tt.policy)
+			assert.DeepEqual(t, out.Resources, tt.expected)
+		})
+	}
+	var nilRes *Resource
+	assert.Assert(t, nilRes.CoalesceAliases(aliases, MergePolicySum) == nil, "nil resource should return nil")
+	assert.Equal(t, res.Resources["nvidia.com/gpu"], Quantity(2), "input resource changed")
+}
+
 func TestToString(t *testing.T) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -2257,6 +4946,84 @@ func TestHasNegativeValue(t *testing.T) {
 	}
 }
 
+func TestAllPositive(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *Resource
+		expectedResult bool
+	}{
+		{"Nil resource", nil, false},
+		{"Empty resource", NewResource(), false},
+		{"Only positive values", NewResourceFromMap(map[string]Quantity{common.Memory: 100, common.CPU: 1}), true},
+		{"Zero value", NewResourceFromMap(map[string]Quantity{common.Memory: 100, common.CPU: 0}), false},
+		{"Negative value", NewResourceFromMap(map[string]Quantity{common.Memory: 100, common.CPU: -1}), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedResult, tc.input.AllPositive())
+		})
+	}
+}
+
+func TestAllNonNegative(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          *Resource
+		expectedResult bool
+	}{
+		{"Nil resource", nil, true},
+		{"Empty resource", NewResource(), true},
+		{"Only positive values", NewResourceFromMap(map[string]Quantity{common.Memory: 100, common.CPU: 1}), true},
+		{"Zero value", NewResourceFromMap(map[string]Quantity{common.Memory: 100, common.CPU: 0}), true},
+		{"Negative value", NewResourceFromMap(map[string]Quantity{common.Memory: 100, common.CPU: -1}), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedResult, tc.input.AllNonNegative())
+		})
+	}
+}
+
+func TestNonZeroCount(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *Resource
+		nonZeroCount  int
+		positiveCount int
+	}{
+		{"Nil resource", nil, 0, 0},
+		{"Empty resource", NewResource(), 0, 0},
+		{"Zero values", NewResourceFromMap(map[string]Quantity{common.Memory: 0, common.CPU: 0}), 0, 0},
+		{"Positive value", NewResourceFromMap(map[string]Quantity{common.Memory: 100, common.CPU: 0}), 1, 1},
+		{"Negative value", NewResourceFromMap(map[string]Quantity{common.Memory: -100, common.CPU: 0}), 1, 0},
+		{"Mixed values", NewResourceFromMap(map[string]Quantity{common.Memory: -100, common.CPU: 10, "pods": 1, "zero": 0}), 3, 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.nonZeroCount, tc.input.NonZeroCount())
+			assert.Equal(t, tc.positiveCount, tc.input.PositiveCount())
+		})
+	}
+}
+
+func TestSigns(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    *Resource
+		expected map[string]int
+	}{
+		{"Nil resource", nil, map[string]int{}},
+		{"Empty resource", NewResource(), map[string]int{}},
+		{"Mixed values", NewResourceFromMap(map[string]Quantity{"first": 100, "second": 0, "third": -1}), map[string]int{"first": 1, "second": 0, "third": -1}},
+		{"Extreme values", NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64, "second": math.MinInt64}), map[string]int{"first": 1, "second": -1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, tc.input.Signs(), tc.expected)
+		})
+	}
+}
+
 func TestIsEmpty(t *testing.T) {
 	testCases := []struct {
 		name           string
@@ -2275,6 +5042,28 @@ func TestIsEmpty(t *testing.T) {
 	}
 }
 
+func TestIsNilAndHasExplicitZeros(t *testing.T) {
+	testCases := []struct {
+		name          string
+		input         *Resource
+		expectedNil   bool
+		expectedZeros bool
+	}{
+		{"Nil resource", nil, true, false},
+		{"Empty resource", NewResource(), false, false},
+		{"Zero value", NewResourceFromMap(map[string]Quantity{common.Memory: 0}), false, true},
+		{"Zero and positive value", NewResourceFromMap(map[string]Quantity{common.Memory: 0, common.CPU: 10}), false, true},
+		{"Positive value", NewResourceFromMap(map[string]Quantity{common.Memory: 100}), false, false},
+		{"Negative value", NewResourceFromMap(map[string]Quantity{common.Memory: -100}), false, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedNil, IsNil(tc.input))
+			assert.Equal(t, tc.expectedZeros, tc.input.HasExplicitZeros())
+		})
+	}
+}
+
 func TestResource_DominantResource(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -2301,6 +5090,83 @@ func TestResource_DominantResource(t *testing.T) {
 	}
 }
 
+func TestResource_RankedResourceTypes(t *testing.T) {
+	tests := []struct {
+		name     string
+		used     *Resource
+		capacity *Resource
+		want     []string
+	}{
+		{"nil receiver", nil, Zero, []string{}},
+		{"nil cap", Zero, nil, []string{}},
+		{"zero cap", NewResourceFromMap(map[string]Quantity{"A": 10}), Zero, []string{}},
+		{"usage not in cap", NewResourceFromMap(map[string]Quantity{"B": 10, "A": 5}), NewResourceFromMap(map[string]Quantity{"A": 10}), []string{"A"}},
+		{"descending ratio", NewResourceFromMap(map[string]Quantity{"A": 1, "B": 5, "C": 20}), NewResourceFromMap(map[string]Quantity{"A": 10, "B": 10, "C": 10}), []string{"C", "B", "A"}},
+		{"equal ratio sorted", NewResourceFromMap(map[string]Quantity{"C": 5, "A": 5, "B": 10}), NewResourceFromMap(map[string]Quantity{"A": 10, "B": 20, "C": 10}), []string{"A", "B", "C"}},
+		{"usage with 0 cap", NewResourceFromMap(map[string]Quantity{"A": 10, "B": 5, "C": 0}), NewResourceFromMap(map[string]Quantity{"A": 0, "B": 10, "C": 0}), []string{"A", "B", "C"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.DeepEqual(t, tt.used.RankedResourceTypes(tt.capacity), tt.want)
+			if len(tt.want) != 0 {
+				assert.Equal(t, tt.used.DominantResourceType(tt.capacity) != "", true, "dominant type expected")
+			}
+		})
+	}
+}
+
+func TestSkew(t *testing.T) {
+	capacity := NewResourceFromMap(map[string]Quantity{"A": 100, "B": 10, "C": 0})
+	tests := map[string]struct {
+		res      *Resource
+		capacity *Resource
+		expected float64
+	}{
+		"nil resource":    {res: nil, capacity: capacity, expected: 0},
+		"nil capacity":    {res: NewResourceFromMap(map[string]Quantity{"A": 10, "B": 5}), capacity: nil, expected: 0},
+		"single type":     {res: NewResourceFromMap(map[string]Quantity{"A": 90}), capacity: capacity, expected: 0},
+		"balanced":        {res: NewResourceFromMap(map[string]Quantity{"A": 50, "B": 5}), capacity: capacity, expected: 0},
+		"skewed":          {res: NewResourceFromMap(map[string]Quantity{"A": 10, "B": 5}), capacity: capacity, expected: 0.4},
+		"zero capacity":   {res: NewResourceFromMap(map[string]Quantity{"A": 25, "C": 1}), capacity: capacity, expected: 0.75},
+		"undefined types": {res: NewResourceFromMap(map[string]Quantity{"A": 10, "D": 1000}), capacity: capacity, expected: 0},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Assert(t, math.Abs(tt.res.Skew(tt.capacity)-tt.expected) < 1e-9, "unexpected skew: got %f, expected %f", tt.res.Skew(tt.capacity), tt.expected)
+		})
+	}
+}
+
+func TestSortByDominantShare(t *testing.T) {
+	capacity := NewResourceFromMap(map[string]Quantity{"A": 100, "B": 10})
+	high := NewResourceFromMap(map[string]Quantity{"A": 10, "B": 5})
+	low := NewResourceFromMap(map[string]Quantity{"A": 20, "B": 1})
+	tieSmall := NewResourceFromMap(map[string]Quantity{"A": 30})
+	tieLarge := NewResourceFromMap(map[string]Quantity{"A": 30, "B": 2})
+	undefined := NewResourceFromMap(map[string]Quantity{"C": 1000})
+	stableFirst := NewResourceFromMap(map[string]Quantity{"A": 30})
+
+	list := []*Resource{high, tieLarge, nil, tieSmall, low, stableFirst, undefined}
+	SortByDominantShare(list, capacity)
+	expected := []*Resource{nil, undefined, low, tieSmall, stableFirst, tieLarge, high}
+	assert.Equal(t, len(list), len(expected), "sort changed the length of the list")
+	for i := range expected {
+		assert.Assert(t, list[i] == expected[i], "unexpected resource at index %d: got %v, expected %v", i, list[i], expected[i])
+	}
+
+	// nil capacity: all shares are 0 only the norm is used
+	list = []*Resource{high, low, tieSmall}
+	SortByDominantShare(list, nil)
+	expected = []*Resource{high, low, tieSmall}
+	for i := range expected {
+		assert.Assert(t, list[i] == expected[i], "unexpected resource at index %d: got %v, expected %v", i, list[i], expected[i])
+	}
+
+	// empty and nil list must not panic
+	SortByDominantShare(nil, capacity)
+	SortByDominantShare([]*Resource{}, capacity)
+}
+
 func TestResource_PruneNil(t *testing.T) {
 	// make sure we're nil safe IDE will complain about the receiver being nil
 	defer func() {
@@ -2332,3 +5198,64 @@ func TestResource_Prune(t *testing.T) {
 		})
 	}
 }
+
+func TestResource_PruneReporting(t *testing.T) {
+	var empty *Resource
+	assert.DeepEqual(t, empty.PruneReporting(), []string{})
+
+	var tests = []struct {
+		caseName string
+		input    map[string]Quantity
+		output   map[string]Quantity
+		removed  []string
+	}{
+		{"no types", map[string]Quantity{}, map[string]Quantity{}, []string{}},
+		{"all types with value", map[string]Quantity{"first": 1, "second": -2, "third": 3}, map[string]Quantity{"first": 1, "second": -2, "third": 3}, []string{}},
+		{"zero type", map[string]Quantity{"first": 1, "zero": 0, "third": 3}, map[string]Quantity{"first": 1, "third": 3}, []string{"zero"}},
+		{"no types with value", map[string]Quantity{"second": 0, "first": 0, "third": 0}, map[string]Quantity{}, []string{"first", "second", "third"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.caseName, func(t *testing.T) {
+			original := NewResourceFromMap(tt.input)
+			removed := original.PruneReporting()
+			assert.DeepEqual(t, removed, tt.removed)
+			assert.Assert(t, maps.Equal(original.Resources, tt.output), "resource type maps are not equal")
+		})
+	}
+}
+
+func TestResource_SetRemoveNil(t *testing.T) {
+	// make sure we're nil safe IDE will complain about the receiver being nil
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatal("panic on nil resource in set or remove test")
+		}
+	}()
+	var empty *Resource
+	empty.Set("first", 1)
+	empty.Remove("first")
+	assert.Assert(t, empty == nil, "nil resource should not be changed")
+}
+
+func TestResource_Set(t *testing.T) {
+	// no map allocated
+	res := &Resource{}
+	res.Set("first", 1)
+	assert.Assert(t, maps.Equal(res.Resources, map[string]Quantity{"first": 1}), "set on resource without map failed")
+	// overwrite and add
+	res.Set("first", -1)
+	res.Set("second", 2)
+	assert.Assert(t, maps.Equal(res.Resources, map[string]Quantity{"first": -1, "second": 2}), "set on resource with map failed")
+}
+
+func TestResource_Remove(t *testing.T) {
+	// no map allocated
+	res := &Resource{}
+	res.Remove("first")
+	assert.Equal(t, len(res.Resources), 0, "remove on resource without map failed")
+	res = NewResourceFromMap(map[string]Quantity{"first": 1, "second": 2})
+	res.Remove("unknown")
+	assert.Assert(t, maps.Equal(res.Resources, map[string]Quantity{"first": 1, "second": 2}), "remove of unknown type changed resource")
+	res.Remove("first")
+	assert.Assert(t, maps.Equal(res.Resources, map[string]Quantity{"second": 2}), "remove of existing type failed")
+}
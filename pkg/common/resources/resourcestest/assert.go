@@ -0,0 +1,60 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package resourcestest provides helpers for tests that compare resources.
+package resourcestest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+// AssertEqual fails the test if the expected and actual resources are not equal based on resources.DeepEquals.
+// The failure message lists each type that was added, removed or changed with its expected and actual value.
+// A nil resource is only equal to another nil resource, the diff treats a nil resource as an empty resource.
+func AssertEqual(t testing.TB, expected, actual *resources.Resource) {
+	t.Helper()
+	if resources.DeepEquals(expected, actual) {
+		return
+	}
+	t.Fatalf("resources not equal: expected %s, actual %s%s", expected, actual, diffMessage(expected, actual))
+}
+
+// diffMessage returns the per type differences between the two resources, one type per line sorted by type.
+func diffMessage(expected, actual *resources.Resource) string {
+	added, removed, changed := resources.Diff(expected, actual)
+	var lines []string
+	for k, v := range added.Resources {
+		lines = append(lines, fmt.Sprintf("%s: unexpected type, actual %d", k, v))
+	}
+	for k, v := range removed.Resources {
+		lines = append(lines, fmt.Sprintf("%s: missing type, expected %d", k, v))
+	}
+	for k := range changed.Resources {
+		lines = append(lines, fmt.Sprintf("%s: expected %d, actual %d", k, expected.Resources[k], actual.Resources[k]))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	sort.Strings(lines)
+	return "\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,69 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resourcestest
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/apache/yunikorn-core/pkg/common/resources"
+)
+
+// fakeTB records a failure instead of stopping the test.
+type fakeTB struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	f.failed = true
+	f.message = fmt.Sprintf(format, args...)
+}
+
+func TestAssertEqual(t *testing.T) {
+	tests := map[string]struct {
+		expected *resources.Resource
+		actual   *resources.Resource
+		message  string
+	}{
+		"both nil": {nil, nil, ""},
+		"same":     {resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1, "second": 0}), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1, "second": 0}), ""},
+		"expected nil": {nil, resources.NewResource(),
+			"resources not equal: expected nil resource, actual map[]"},
+		"actual nil": {resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1}), nil,
+			"resources not equal: expected map[first:1], actual nil resource\nfirst: missing type, expected 1"},
+		"zero value": {resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1}), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1, "second": 0}),
+			"resources not equal: expected map[first:1], actual map[first:1 second:0]\nsecond: unexpected type, actual 0"},
+		"all differences": {resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1, "second": 2, "third": 3}), resources.NewResourceFromMap(map[string]resources.Quantity{"first": 1, "second": 5, "fourth": 4}),
+			"resources not equal: expected map[first:1 second:2 third:3], actual map[first:1 fourth:4 second:5]\nfourth: unexpected type, actual 4\nsecond: expected 2, actual 5\nthird: missing type, expected 3"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tb := &fakeTB{TB: t}
+			AssertEqual(tb, test.expected, test.actual)
+			assert.Equal(t, tb.failed, test.message != "", "unexpected assert result")
+			assert.Equal(t, tb.message, test.message, "unexpected failure message")
+		})
+	}
+}
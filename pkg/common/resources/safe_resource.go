@@ -0,0 +1,98 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/apache/yunikorn-core/pkg/locking"
+)
+
+// SafeResource is a utility struct to accumulate resources from multiple goroutines.
+// All access to the wrapped resource is protected by the internal lock, the caller does not need to lock.
+// The zero value is ready to use and has an empty total.
+type SafeResource struct {
+	total *Resource
+
+	locking.RWMutex
+}
+
+// NewSafeResource creates a new instance of SafeResource with an empty total.
+func NewSafeResource() *SafeResource {
+	return &SafeResource{
+		total: NewResource(),
+	}
+}
+
+func (sr *SafeResource) String() string {
+	if sr == nil {
+		return "SafeResource{}"
+	}
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.total == nil {
+		return "SafeResource{total=map[]}"
+	}
+	return fmt.Sprintf("SafeResource{total=%s}", sr.total)
+}
+
+// Add adds the resource to the total. A nil resource does not change the total.
+// The addition is protected from overflow (positive and negative).
+func (sr *SafeResource) Add(res *Resource) {
+	if res == nil {
+		return
+	}
+	sr.Lock()
+	defer sr.Unlock()
+	if sr.total == nil {
+		sr.total = NewResource()
+	}
+	sr.total.AddTo(res)
+}
+
+// Sub subtracts the resource from the total. A nil resource does not change the total.
+// The subtraction is protected from overflow (positive and negative) and might result in negative values.
+func (sr *SafeResource) Sub(res *Resource) {
+	if res == nil {
+		return
+	}
+	sr.Lock()
+	defer sr.Unlock()
+	if sr.total == nil {
+		sr.total = NewResource()
+	}
+	sr.total.SubFrom(res)
+}
+
+// Snapshot returns a clone of the total. Changes to the returned resource do not affect the total.
+func (sr *SafeResource) Snapshot() *Resource {
+	sr.RLock()
+	defer sr.RUnlock()
+	if sr.total == nil {
+		return NewResource()
+	}
+	return sr.total.Clone()
+}
+
+// Reset removes all types from the total leaving an empty resource.
+func (sr *SafeResource) Reset() {
+	sr.Lock()
+	defer sr.Unlock()
+	sr.total = NewResource()
+}
@@ -0,0 +1,92 @@
+/*
+ Licensed to the Apache Software Foundation (ASF) under one
+ or more contributor license agreements.  See the NOTICE file
+ distributed with this work for additional information
+ regarding copyright ownership.  The ASF licenses this file
+ to you under the Apache License, Version 2.0 (the
+ "License"); you may not use this file except in compliance
+ with the License.  You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package resources
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestNewSafeResource(t *testing.T) {
+	sr := NewSafeResource()
+	assert.Assert(t, IsZero(sr.Snapshot()), "new instance should have an empty total")
+	assert.Equal(t, sr.String(), "SafeResource{total=map[]}")
+
+	var empty *SafeResource
+	assert.Equal(t, empty.String(), "SafeResource{}")
+}
+
+func TestSafeResourceAddSub(t *testing.T) {
+	sr := NewSafeResource()
+	sr.Add(NewResourceFromMap(map[string]Quantity{"first": 5, "second": 1}))
+	sr.Add(nil)
+	sr.Sub(NewResourceFromMap(map[string]Quantity{"first": 2, "third": 1}))
+	sr.Sub(nil)
+	assert.Assert(t, DeepEquals(sr.Snapshot(), NewResourceFromMap(map[string]Quantity{"first": 3, "second": 1, "third": -1})), "unexpected total: %v", sr.Snapshot())
+
+	// snapshot must not change the internal state
+	snapshot := sr.Snapshot()
+	snapshot.Resources["first"] = 100
+	assert.Equal(t, sr.Snapshot().Resources["first"], Quantity(3), "total changed via snapshot")
+
+	// additions are protected from overflow
+	sr.Add(NewResourceFromMap(map[string]Quantity{"first": math.MaxInt64}))
+	assert.Equal(t, sr.Snapshot().Resources["first"], Quantity(math.MaxInt64), "total should not wrap")
+
+	sr.Reset()
+	assert.Equal(t, len(sr.Snapshot().Resources), 0, "reset should remove all types")
+	assert.Equal(t, snapshot.Resources["first"], Quantity(100), "reset changed earlier snapshot")
+}
+
+func TestSafeResourceZeroValue(t *testing.T) {
+	var sr SafeResource
+	assert.Equal(t, sr.String(), "SafeResource{total=map[]}")
+	assert.Assert(t, DeepEquals(sr.Snapshot(), NewResource()), "zero value should have an empty total")
+	sr.Sub(NewResourceFromMap(map[string]Quantity{"first": 2}))
+	sr.Add(NewResourceFromMap(map[string]Quantity{"first": 5}))
+	assert.Assert(t, DeepEquals(sr.Snapshot(), NewResourceFromMap(map[string]Quantity{"first": 3})), "unexpected total: %v", sr.Snapshot())
+
+	// embedded in another struct without a constructor
+	holder := struct {
+		used SafeResource
+	}{}
+	holder.used.Add(NewResourceFromMap(map[string]Quantity{"first": 1}))
+	assert.Assert(t, DeepEquals(holder.used.Snapshot(), NewResourceFromMap(map[string]Quantity{"first": 1})), "unexpected total: %v", holder.used.Snapshot())
+}
+
+func TestSafeResourceConcurrent(t *testing.T) {
+	sr := NewSafeResource()
+	res := NewResourceFromMap(map[string]Quantity{"first": 1})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				sr.Add(res)
+				sr.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, sr.Snapshot().Resources["first"], Quantity(1000), "unexpected total after concurrent updates")
+}